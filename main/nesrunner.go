@@ -1,18 +1,126 @@
 package main
 
 import (
+	"encoding/binary"
+	"errors"
 	"fmt"
 	"goNES/nes"
+	"goNES/patch"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
 	"time"
 
 	"github.com/veandco/go-sdl2/sdl"
 )
 
+// loads the ROM into a cartridge, applying a patch with the same name as the ROM first if there is one
+func loadCartridge(romPath string, entry string) (*nes.Cartridge, error) {
+	data, err := nes.ReadROMEntry(romPath, entry)
+	if err != nil {
+		return nil, err
+	}
+
+	if patchPath := patch.FindPatch(romPath); patchPath != "" {
+		data, err = patch.ApplyFile(data, patchPath)
+		if err != nil {
+			return nil, fmt.Errorf("applying %s: %w", patchPath, err)
+		}
+	}
+
+	db, err := findGameDB(romPath)
+	if err != nil {
+		return nil, err
+	}
+
+	//the database corrects the header before the mapper is made, so a wrong mapper number in the header doesn't stop the game loading
+	cart, err := nes.LoadCartridgeBytesDB(data, db)
+	if err != nil {
+		return nil, err
+	}
+
+	cart.SavePath = nes.SavePathFor(romPath)
+	if err := cart.LoadSave(); err != nil {
+		return nil, err
+	}
+
+	return cart, nil
+}
+
+// loads the game database, which can be next to the ROM or in the working directory, returns nil if there isn't one
+func findGameDB(romPath string) (*nes.GameDB, error) {
+	for _, dbPath := range []string{filepath.Join(filepath.Dir(romPath), gameDBName), gameDBName} {
+		db, err := nes.LoadGameDBFile(dbPath)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("loading %s: %w", dbPath, err)
+		}
+		return db, nil
+	}
+
+	return nil, nil
+}
+
+// opens the default audio device for the mono float samples the bus makes
+func openAudio(sampleRate float64) (sdl.AudioDeviceID, error) {
+	spec := sdl.AudioSpec{
+		Freq:     int32(sampleRate),
+		Format:   sdl.AUDIO_F32SYS,
+		Channels: 1,
+		Samples:  1024,
+	}
+	device, err := sdl.OpenAudioDevice("", false, &spec, nil, 0)
+	if err != nil {
+		return 0, err
+	}
+	sdl.PauseAudioDevice(device, false)
+
+	return device, nil
+}
+
+// sends the samples made since the last frame to the audio device
+func queueAudio(device sdl.AudioDeviceID, samples []float32) error {
+	data := make([]byte, len(samples)*4)
+	for i, sample := range samples {
+		binary.NativeEndian.PutUint32(data[i*4:], math.Float32bits(sample))
+	}
+
+	return sdl.QueueAudio(device, data)
+}
+
+// name of the NES 2.0 header database file used to fix bad headers
+const gameDBName = "nes20db.xml"
+
+// how often the battery backed RAM is written to the save file
+const saveInterval = 5 * time.Second
+
 func main() {
 	bus := nes.CreateBus()
-	cart := nes.CreateCartridge("../dk.nes")
+	//the ROM can be given on the command line, optionally followed by which file to use inside a .zip
+	romPath := "../dk.nes"
+	if len(os.Args) > 1 {
+		romPath = os.Args[1]
+	}
+	entry := ""
+	if len(os.Args) > 2 {
+		entry = os.Args[2]
+	}
+
+	cart, err := loadCartridge(romPath, entry)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
 	fmt.Println(cart)
-	sdl.Init(sdl.INIT_VIDEO)
+	sdl.Init(sdl.INIT_VIDEO | sdl.INIT_AUDIO)
+	//the game still runs without sound if there's no audio device
+	audio, err := openAudio(bus.SampleRate)
+	if err != nil {
+		fmt.Println(err)
+	}
 	window, renderer, err := sdl.CreateWindowAndRenderer(640, 480, 0)
 
 	closeRequested := false
@@ -47,6 +155,11 @@ func main() {
 	// rect := sdl.Rect{X: 50, Y: 50, W: 50, H: 50}
 	// renderer.Copy(tex, nil, &rect)
 	// fmt.Println()
+	//battery backed saves are flushed to disk every so often so a crash doesn't lose much progress
+	lastSave := time.Now()
+	//a KIL opcode stops the CPU for good, which is only told once
+	haltReported := false
+
 	for !closeRequested {
 		renderer.SetDrawColor(0, 0, 0, 255)
 		renderer.Clear()
@@ -55,6 +168,17 @@ func main() {
 			bus.Clock()
 		}
 		bus.PPU.Complete = false
+		if bus.CPU.Halted() && !haltReported {
+			fmt.Println("the CPU ran a KIL opcode and stopped")
+			haltReported = true
+		}
+		if audio != 0 {
+			if err := queueAudio(audio, bus.AudioSamples()); err != nil {
+				fmt.Println(err)
+			}
+		} else {
+			bus.AudioSamples()
+		}
 		fmt.Println("sleep")
 		time.Sleep(2 * time.Second)
 
@@ -64,8 +188,22 @@ func main() {
 		if event != nil && event.GetType() == sdl.QUIT {
 			closeRequested = true
 		}
+
+		if time.Since(lastSave) >= saveInterval {
+			if err := cart.Save(); err != nil {
+				fmt.Println(err)
+			}
+			lastSave = time.Now()
+		}
 	}
 
+	if err := cart.Save(); err != nil {
+		fmt.Println(err)
+	}
+
+	if audio != 0 {
+		sdl.CloseAudioDevice(audio)
+	}
 	renderer.Destroy()
 	window.Destroy()
 	sdl.Quit()
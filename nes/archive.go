@@ -0,0 +1,102 @@
+package nes
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+)
+
+// returned when a .zip archive doesn't have a ROM in it, or doesn't have the entry that was asked for
+var ErrNoROMInArchive = errors.New("no ROM found in archive")
+
+// file extensions that are picked out of a .zip when no entry is named
+// .nsf music rips are left out, the cartridge loader can't play them so a ROM next to one should win
+var romExtensions = []string{".nes", ".unf"}
+
+// reads a ROM image from a file, unpacking it first if it's in a .zip or .gz archive
+func ReadROM(filename string) ([]byte, error) {
+	return ReadROMEntry(filename, "")
+}
+
+// same as ReadROM, but entry names the file to take out of a .zip, if it's empty the first .nes or .unf file is used
+// the archive type is found from the start of the file rather than the extension, so misnamed files still open
+func ReadROMEntry(filename string, entry string) ([]byte, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case bytes.HasPrefix(data, []byte("PK\x03\x04")):
+		return readZipROM(data, entry)
+	case bytes.HasPrefix(data, []byte{0x1f, 0x8b}):
+		return readGzipROM(data)
+	}
+
+	return data, nil
+}
+
+// finds the ROM inside of a zip archive and decompresses it
+func readZipROM(data []byte, entry string) ([]byte, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, file := range archive.File {
+		if file.FileInfo().IsDir() {
+			continue
+		}
+
+		if entry != "" {
+			//named entries can be given with or without the folder they are in
+			if file.Name != entry && path.Base(file.Name) != entry {
+				continue
+			}
+		} else if !isROMName(file.Name) {
+			continue
+		}
+
+		rom, err := file.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer rom.Close()
+
+		return io.ReadAll(rom)
+	}
+
+	if entry != "" {
+		return nil, fmt.Errorf("%w: %s", ErrNoROMInArchive, entry)
+	}
+	return nil, ErrNoROMInArchive
+}
+
+// decompresses a gzip file, which only ever holds one file
+func readGzipROM(data []byte) ([]byte, error) {
+	rom, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer rom.Close()
+
+	return io.ReadAll(rom)
+}
+
+// checks if a file name has one of the ROM extensions
+func isROMName(name string) bool {
+	ext := strings.ToLower(path.Ext(name))
+	for _, romExt := range romExtensions {
+		if ext == romExt {
+			return true
+		}
+	}
+
+	return false
+}
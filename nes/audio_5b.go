@@ -0,0 +1,159 @@
+package nes
+
+import "math"
+
+// the Sunsoft 5B's sound, a YM2149F, which is an AY-3-8910 with 3 square channels, a noise generator and an envelope
+// the 5B's envelope and volumes have 32 steps of 1.5 dB where the AY has 16 of 3 dB
+
+type sunsoft5BAudio struct {
+	//the register the next data write goes to, and the 16 registers
+	address   uint8
+	registers [16]uint8
+
+	//the tone counters and the square wave each channel is putting out
+	toneTimers [3]uint16
+	toneHigh   [3]bool
+
+	noiseTimer uint8
+	//17 bit shift register, the low bit is the noise output
+	noiseShift uint32
+
+	envelopeTimer uint32
+	//the step through the envelope, from 0 to 31, and if it's going down instead of up
+	envelopeStep    uint8
+	envelopeFalling bool
+	envelopeHeld    bool
+
+	//the chip divides the CPU clock by 16
+	cycles uint8
+}
+
+// the amplitude of each of the 32 levels, each 1.5 dB quieter than the next
+var sunsoft5BLevels = func() [32]float32 {
+	var levels [32]float32
+	for i := 1; i < 32; i++ {
+		levels[i] = float32(math.Pow(10, -1.5*float64(31-i)/20))
+	}
+	return levels
+}()
+
+func (audio *sunsoft5BAudio) writeAddress(data uint8) {
+	audio.address = data & 0x0f
+}
+
+func (audio *sunsoft5BAudio) writeData(data uint8) {
+	audio.registers[audio.address] = data
+	//writing the shape restarts the envelope
+	if audio.address == 13 {
+		audio.envelopeStep = 0
+		audio.envelopeHeld = false
+		audio.envelopeFalling = data&0x04 == 0
+		audio.envelopeTimer = 0
+	}
+}
+
+func (audio *sunsoft5BAudio) clock() {
+	audio.cycles++
+	if audio.cycles < 16 {
+		return
+	}
+	audio.cycles = 0
+
+	//each channel's square wave flips every time its 12 bit period runs out
+	for channel := range audio.toneTimers {
+		period := uint16(audio.registers[channel*2]) | uint16(audio.registers[channel*2+1]&0x0f)<<8
+		audio.toneTimers[channel]++
+		if audio.toneTimers[channel] >= period {
+			audio.toneTimers[channel] = 0
+			audio.toneHigh[channel] = !audio.toneHigh[channel]
+		}
+	}
+
+	//the noise moves at half the rate of the tones
+	audio.noiseTimer++
+	if audio.noiseTimer >= (audio.registers[6]&0x1f)*2 {
+		audio.noiseTimer = 0
+		if audio.noiseShift == 0 {
+			audio.noiseShift = 1
+		}
+		feedback := (audio.noiseShift ^ audio.noiseShift>>3) & 1
+		audio.noiseShift = audio.noiseShift>>1 | feedback<<16
+	}
+
+	//the envelope's 32 steps take as long as the AY's 16
+	audio.envelopeTimer++
+	period := uint32(audio.registers[11]) | uint32(audio.registers[12])<<8
+	if audio.envelopeTimer >= period/2 {
+		audio.envelopeTimer = 0
+		audio.clockEnvelope()
+	}
+}
+
+// moves the envelope a step, the shape register is CONT ATT ALT HOLD
+func (audio *sunsoft5BAudio) clockEnvelope() {
+	if audio.envelopeHeld {
+		return
+	}
+
+	audio.envelopeStep++
+	if audio.envelopeStep < 32 {
+		return
+	}
+
+	shape := audio.registers[13]
+	switch {
+	case shape&0x08 == 0:
+		//without continue the envelope stops at 0 after one go
+		audio.envelopeHeld = true
+		audio.envelopeFalling = true
+		audio.envelopeStep = 31
+	case shape&0x01 == 0x01:
+		//hold stays at the end of the first go, which alternate flips
+		audio.envelopeHeld = true
+		if shape&0x02 == 0x02 {
+			audio.envelopeFalling = !audio.envelopeFalling
+		}
+		audio.envelopeStep = 31
+	default:
+		audio.envelopeStep = 0
+		if shape&0x02 == 0x02 {
+			audio.envelopeFalling = !audio.envelopeFalling
+		}
+	}
+}
+
+// the envelope's level, from 0 to 31
+func (audio *sunsoft5BAudio) envelopeLevel() uint8 {
+	if audio.envelopeFalling {
+		return 31 - audio.envelopeStep
+	}
+	return audio.envelopeStep
+}
+
+// mixes the 3 channels, each channel is on when its enabled tone and noise are both high
+func (audio *sunsoft5BAudio) sample() float32 {
+	mixer := audio.registers[7]
+	noiseHigh := audio.noiseShift&1 == 1
+
+	total := float32(0)
+	for channel := 0; channel < 3; channel++ {
+		toneOff := mixer&(1<<channel) != 0
+		noiseOff := mixer&(0x08<<channel) != 0
+		if !(toneOff || audio.toneHigh[channel]) || !(noiseOff || noiseHigh) {
+			continue
+		}
+
+		//the volume register is in 3 dB steps, so it's doubled to be a level, unless the envelope is used
+		volume := audio.registers[8+channel]
+		level := (volume&0x0f)*2 + 1
+		if volume&0x0f == 0 {
+			level = 0
+		}
+		if volume&0x10 == 0x10 {
+			level = audio.envelopeLevel()
+		}
+		total += sunsoft5BLevels[level]
+	}
+
+	return total / 3 * 0.5
+}
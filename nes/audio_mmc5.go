@@ -0,0 +1,174 @@
+package nes
+
+// the MMC5's sound, 2 pulse channels that work like the APU's without the sweep, and an 8 bit PCM channel
+
+// lengths the length counters are loaded with, indexed by the top 5 bits of the 4th pulse register, the same as the APU
+var lengthTable = [32]uint8{
+	10, 254, 20, 2, 40, 4, 80, 6, 160, 8, 60, 10, 14, 12, 26, 14,
+	12, 16, 24, 18, 48, 20, 96, 22, 192, 24, 72, 26, 16, 28, 32, 30,
+}
+
+// the 8 step waveforms for each duty cycle, 12.5%, 25%, 50% and 75%
+var pulseDuty = [4][8]uint8{
+	{0, 1, 0, 0, 0, 0, 0, 0},
+	{0, 1, 1, 0, 0, 0, 0, 0},
+	{0, 1, 1, 1, 1, 0, 0, 0},
+	{1, 0, 0, 1, 1, 1, 1, 1},
+}
+
+type mmc5Pulse struct {
+	enabled bool
+
+	//DDLC VVVV, duty, length counter halt and envelope loop, constant volume, volume or envelope period
+	control uint8
+	//11 bit timer period, and the counter counting down to the next step of the waveform
+	period uint16
+	timer  uint16
+	step   uint8
+
+	length uint8
+
+	//the envelope restarts when the 4th register is written
+	envelopeStart   bool
+	envelopeDivider uint8
+	envelopeVolume  uint8
+}
+
+// writes one of the 4 registers of a pulse channel, the second register would be the sweep, which the MMC5 doesn't have
+func (pulse *mmc5Pulse) write(reg uint16, data uint8) {
+	switch reg {
+	case 0:
+		pulse.control = data
+	case 2:
+		pulse.period = (pulse.period & 0x0700) | uint16(data)
+	case 3:
+		pulse.period = (pulse.period & 0x00ff) | uint16(data&0x07)<<8
+		if pulse.enabled {
+			pulse.length = lengthTable[data>>3]
+		}
+		pulse.step = 0
+		pulse.envelopeStart = true
+	}
+}
+
+// clocked every other CPU cycle, moves through the waveform each time the timer runs out
+func (pulse *mmc5Pulse) clockTimer() {
+	if pulse.timer == 0 {
+		pulse.timer = pulse.period
+		pulse.step = (pulse.step + 1) & 0x07
+	} else {
+		pulse.timer--
+	}
+}
+
+// clocked about 240 times a second, fades the volume and counts down the length
+func (pulse *mmc5Pulse) clockEnvelopeAndLength() {
+	loop := pulse.control&0x20 == 0x20
+
+	if pulse.envelopeStart {
+		pulse.envelopeStart = false
+		pulse.envelopeVolume = 15
+		pulse.envelopeDivider = pulse.control & 0x0f
+	} else if pulse.envelopeDivider == 0 {
+		pulse.envelopeDivider = pulse.control & 0x0f
+		if pulse.envelopeVolume > 0 {
+			pulse.envelopeVolume--
+		} else if loop {
+			pulse.envelopeVolume = 15
+		}
+	} else {
+		pulse.envelopeDivider--
+	}
+
+	if !loop && pulse.length > 0 {
+		pulse.length--
+	}
+}
+
+// the current output of the channel, from 0 to 15
+func (pulse *mmc5Pulse) output() uint8 {
+	if pulse.length == 0 || pulseDuty[pulse.control>>6][pulse.step] == 0 {
+		return 0
+	}
+	if pulse.control&0x10 == 0x10 {
+		return pulse.control & 0x0f
+	}
+	return pulse.envelopeVolume
+}
+
+type mmc5Audio struct {
+	pulses [2]mmc5Pulse
+
+	//I--- ---M, PCM IRQ enable, PCM read mode
+	pcmControl uint8
+	pcm        uint8
+
+	//counts CPU cycles for the timers, which run at half the CPU rate, and for the 240 Hz envelope and length clock
+	cycle      uint64
+	frameTimer uint16
+}
+
+// CPU cycles between each clock of the envelopes and length counters, close to the APU's 240 Hz frame counter
+const mmc5FrameCycles = 7457
+
+// writes the audio registers at 0x5000 - 0x5015
+func (audio *mmc5Audio) write(addr uint16, data uint8) {
+	switch {
+	case addr <= 0x5003:
+		audio.pulses[0].write(addr-0x5000, data)
+	case addr >= 0x5004 && addr <= 0x5007:
+		audio.pulses[1].write(addr-0x5004, data)
+	case addr == 0x5010:
+		audio.pcmControl = data
+	case addr == 0x5011:
+		//only in write mode, and 0 is ignored since it's used to trigger the IRQ in read mode
+		if audio.pcmControl&0x01 == 0 && data != 0 {
+			audio.pcm = data
+		}
+	case addr == 0x5015:
+		for i := range audio.pulses {
+			audio.pulses[i].enabled = data&(1<<i) != 0
+			if !audio.pulses[i].enabled {
+				audio.pulses[i].length = 0
+			}
+		}
+	}
+}
+
+// reading 0x5015 gives which pulse channels are still playing
+func (audio *mmc5Audio) status() uint8 {
+	status := uint8(0)
+	for i := range audio.pulses {
+		if audio.pulses[i].length > 0 {
+			status |= 1 << i
+		}
+	}
+	return status
+}
+
+func (audio *mmc5Audio) clock() {
+	if audio.cycle%2 == 0 {
+		audio.pulses[0].clockTimer()
+		audio.pulses[1].clockTimer()
+	}
+	audio.cycle++
+
+	audio.frameTimer++
+	if audio.frameTimer >= mmc5FrameCycles {
+		audio.frameTimer = 0
+		audio.pulses[0].clockEnvelopeAndLength()
+		audio.pulses[1].clockEnvelopeAndLength()
+	}
+}
+
+// mixes the channels, the pulses use the same non-linear mix as the APU's pulses
+func (audio *mmc5Audio) sample() float32 {
+	pulses := float32(audio.pulses[0].output()) + float32(audio.pulses[1].output())
+	out := float32(0)
+	if pulses > 0 {
+		out = 95.88 / (8128/pulses + 100)
+	}
+	out += float32(audio.pcm) / 255 * 0.4
+
+	return out
+}
@@ -0,0 +1,96 @@
+package nes
+
+// the Namco 163's sound, up to 8 wavetable channels that play 4 bit samples out of the chip's 128 bytes of RAM
+// the channel registers are at the end of the RAM, channel 7 at 0x78 - 0x7f down to channel 0 at 0x40 - 0x47
+// the chip only updates one channel at a time, so the more channels are on the lower each one's sample rate
+
+type n163Audio struct {
+	ram [128]uint8
+	//the RAM address the data port uses, and if it goes up after each access
+	address   uint8
+	increment bool
+
+	//CPU cycles since the last channel was updated, the next channel to update, and the last output of each channel
+	cycles  int
+	channel int
+	outputs [8]float32
+}
+
+// each channel update takes 15 CPU cycles
+const n163ChannelCycles = 15
+
+func (audio *n163Audio) writeAddress(data uint8) {
+	audio.address = data & 0x7f
+	audio.increment = data&0x80 == 0x80
+}
+
+// reads or writes the RAM at the data port's address, moving it along if auto increment is on
+func (audio *n163Audio) access(write bool, data uint8) uint8 {
+	if write {
+		audio.ram[audio.address] = data
+	} else {
+		data = audio.ram[audio.address]
+	}
+	if audio.increment {
+		audio.address = (audio.address + 1) & 0x7f
+	}
+	return data
+}
+
+// how many channels are playing, from the top bits of the last register
+func (audio *n163Audio) channels() int {
+	return int((audio.ram[0x7f]>>4)&0x07) + 1
+}
+
+func (audio *n163Audio) clock() {
+	audio.cycles++
+	if audio.cycles < n163ChannelCycles {
+		return
+	}
+	audio.cycles = 0
+
+	//the playing channels are the highest numbered ones
+	count := audio.channels()
+	audio.channel++
+	if audio.channel >= count {
+		audio.channel = 0
+	}
+	audio.updateChannel(7 - audio.channel)
+}
+
+// moves a channel along its waveform and works out its new output
+func (audio *n163Audio) updateChannel(channel int) {
+	regs := audio.ram[0x40+channel*8 : 0x48+channel*8]
+
+	//18 bit frequency and 24 bit phase, the top 8 bits of the phase are the sample in the waveform
+	frequency := uint32(regs[0]) | uint32(regs[2])<<8 | uint32(regs[4]&0x03)<<16
+	phase := uint32(regs[1]) | uint32(regs[3])<<8 | uint32(regs[5])<<16
+	length := 256 - uint32(regs[4]&0xfc)
+
+	phase = (phase + frequency) % (length << 16)
+	regs[1] = uint8(phase)
+	regs[3] = uint8(phase >> 8)
+	regs[5] = uint8(phase >> 16)
+
+	//the waveform is packed 2 samples to a byte, low nibble first
+	sampleAddr := (uint32(regs[6]) + phase>>16) & 0xff
+	sample := audio.ram[sampleAddr/2]
+	if sampleAddr&1 == 1 {
+		sample >>= 4
+	}
+	sample &= 0x0f
+
+	audio.outputs[channel] = (float32(sample) - 8) * float32(regs[7]&0x0f)
+}
+
+// the chip switches between the playing channels, so the output is their average
+func (audio *n163Audio) sample() float32 {
+	count := audio.channels()
+	total := float32(0)
+	for channel := 8 - count; channel < 8; channel++ {
+		total += audio.outputs[channel]
+	}
+
+	//a channel's output is at most 8 * 15 either way
+	return total / float32(count) / 120 * 0.5
+}
@@ -0,0 +1,139 @@
+package nes
+
+// the VRC6's sound, 2 pulse channels with 8 duty cycles and a sawtooth channel
+
+type vrc6Pulse struct {
+	//MDDD VVVV, ignore duty, duty cycle, volume
+	control uint8
+	//E--- PPPP PPPP PPPP, enable, 12 bit period
+	period  uint16
+	enabled bool
+	timer   uint16
+	step    uint8
+}
+
+// writes one of the 3 registers of a pulse channel
+func (pulse *vrc6Pulse) write(reg uint16, data uint8) {
+	switch reg {
+	case 0:
+		pulse.control = data
+	case 1:
+		pulse.period = (pulse.period & 0x0f00) | uint16(data)
+	case 2:
+		pulse.period = (pulse.period & 0x00ff) | uint16(data&0x0f)<<8
+		pulse.enabled = data&0x80 == 0x80
+		//disabling the channel resets its waveform
+		if !pulse.enabled {
+			pulse.step = 15
+		}
+	}
+}
+
+func (pulse *vrc6Pulse) clock(shift uint8) {
+	if !pulse.enabled {
+		return
+	}
+	if pulse.timer == 0 {
+		pulse.timer = pulse.period >> shift
+		if pulse.step == 0 {
+			pulse.step = 15
+		} else {
+			pulse.step--
+		}
+	} else {
+		pulse.timer--
+	}
+}
+
+// the current output of the channel, from 0 to 15, the duty is how many of the 16 steps are on minus 1
+func (pulse *vrc6Pulse) output() uint8 {
+	if !pulse.enabled {
+		return 0
+	}
+	if pulse.control&0x80 == 0x80 || pulse.step <= (pulse.control>>4)&0x07 {
+		return pulse.control & 0x0f
+	}
+	return 0
+}
+
+type vrc6Saw struct {
+	//--AA AAAA, how much the accumulator goes up each step
+	rate    uint8
+	period  uint16
+	enabled bool
+	timer   uint16
+	//the saw goes up over 14 clocks, adding the rate every other clock, then starts over at 0
+	step        uint8
+	accumulator uint8
+}
+
+func (saw *vrc6Saw) write(reg uint16, data uint8) {
+	switch reg {
+	case 0:
+		saw.rate = data & 0x3f
+	case 1:
+		saw.period = (saw.period & 0x0f00) | uint16(data)
+	case 2:
+		saw.period = (saw.period & 0x00ff) | uint16(data&0x0f)<<8
+		saw.enabled = data&0x80 == 0x80
+		if !saw.enabled {
+			saw.step = 0
+			saw.accumulator = 0
+		}
+	}
+}
+
+func (saw *vrc6Saw) clock(shift uint8) {
+	if !saw.enabled {
+		return
+	}
+	if saw.timer > 0 {
+		saw.timer--
+		return
+	}
+
+	saw.timer = saw.period >> shift
+	saw.step++
+	if saw.step >= 14 {
+		saw.step = 0
+		saw.accumulator = 0
+	} else if saw.step%2 == 0 {
+		saw.accumulator += saw.rate
+	}
+}
+
+// the top 5 bits of the accumulator are the output
+func (saw *vrc6Saw) output() uint8 {
+	return saw.accumulator >> 3
+}
+
+type vrc6Audio struct {
+	pulses [2]vrc6Pulse
+	saw    vrc6Saw
+
+	//---- -BAH, the frequency multipliers that shift the periods right by 8 and by 4, halt
+	frequencyControl uint8
+}
+
+func (audio *vrc6Audio) clock() {
+	if audio.frequencyControl&0x01 == 0x01 {
+		return
+	}
+
+	shift := uint8(0)
+	if audio.frequencyControl&0x04 == 0x04 {
+		shift = 8
+	} else if audio.frequencyControl&0x02 == 0x02 {
+		shift = 4
+	}
+
+	audio.pulses[0].clock(shift)
+	audio.pulses[1].clock(shift)
+	audio.saw.clock(shift)
+}
+
+// the VRC6 mixes its channels linearly, the loudest it can be is 15 + 15 + 31
+func (audio *vrc6Audio) sample() float32 {
+	total := float32(audio.pulses[0].output()) + float32(audio.pulses[1].output()) + float32(audio.saw.output())
+	return total / 61 * 0.5
+}
@@ -11,8 +11,19 @@ type Bus struct {
 	Cartridge *Cartridge
 	//2 KB internal ram
 	CPURAM [2048]uint8
+
+	//how many audio samples a second AudioSamples gives
+	SampleRate float64
+	//audio made since it was last collected, and the running total of the cartridge's output for the sample being made
+	samples     []float32
+	audioSum    float32
+	audioCycles int
+	audioClock  float64
 }
 
+// the rate the NTSC CPU runs at, used to turn CPU cycles into audio samples
+const CPUClockRate = 1789773
+
 // uses an uppercase letter at the beginning so its exported
 func CreateBus() *Bus {
 	bus := Bus{}
@@ -20,6 +31,7 @@ func CreateBus() *Bus {
 	bus.CPU.ConnectBus(&bus)
 	bus.PPU = *CreatePPU()
 	bus.PPU.ConnectBus(&bus)
+	bus.SampleRate = 44100
 	return &bus
 }
 
@@ -61,9 +73,11 @@ func (bus Bus) CPURead(addr uint16, readOnly bool) uint8 {
 	return 0x0000
 }
 
+// inserting a cartridge powers it on, so anything the cartridge loads at power on is set up before the CPU resets
 func (bus *Bus) InsertCartridge(cart *Cartridge) {
 	bus.Cartridge = cart
 	bus.PPU.ConnectCartridge(cart)
+	cart.PowerOn()
 }
 
 func (bus *Bus) Clock() {
@@ -71,7 +85,13 @@ func (bus *Bus) Clock() {
 	bus.PPU.Clock()
 
 	if bus.CycleCount%3 == 0 {
+		//the IRQ line is checked between instructions, and stays held until the cartridge lets go of it
+		if bus.CPU.cycles == 0 && bus.Cartridge.IRQ() {
+			bus.CPU.IRQ()
+		}
 		bus.CPU.Clock()
+		bus.Cartridge.CPUClock()
+		bus.clockAudio()
 	}
 
 	//check if the PPU threw an NMI
@@ -85,7 +105,32 @@ func (bus *Bus) Clock() {
 	bus.CycleCount++
 }
 
+// adds the cartridge's audio for this CPU cycle, once enough cycles for an output sample have passed their average becomes the sample
+func (bus *Bus) clockAudio() {
+	bus.audioSum += bus.Cartridge.AudioSample()
+	bus.audioCycles++
+
+	bus.audioClock += bus.SampleRate
+	if bus.audioClock >= CPUClockRate {
+		bus.audioClock -= CPUClockRate
+		bus.samples = append(bus.samples, bus.audioSum/float32(bus.audioCycles))
+		bus.audioSum = 0
+		bus.audioCycles = 0
+	}
+}
+
+// takes the mono audio samples made since the last call, at SampleRate
+// there is no APU yet, so this is only the cartridge's expansion audio
+func (bus *Bus) AudioSamples() []float32 {
+	samples := bus.samples
+	bus.samples = nil
+	return samples
+}
+
 func (bus *Bus) Reset() {
 	bus.CPU.Reset()
+	if bus.Cartridge != nil {
+		bus.Cartridge.Reset()
+	}
 	bus.CycleCount = 0
 }
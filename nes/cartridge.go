@@ -1,8 +1,15 @@
 package nes
 
 import (
+	"bufio"
+	"bytes"
+	"errors"
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
+	"path/filepath"
+	"strings"
 )
 
 // types of mirroring the cartridge can produce
@@ -13,26 +20,96 @@ const (
 	VERTICAL
 	ONESCREEN_LO
 	ONESCREEN_HI
+	//the cartridge has 2 KB of its own nametable memory, so each logical nametable has its own memory
+	FOURSCREEN
+)
+
+// CPU/PPU timing the cartridge was made for
+type Timing uint8
+
+const (
+	NTSC Timing = iota
+	PAL
+	MULTIREGION
+	DENDY
+)
+
+// type of console the cartridge was made for, values past PLAYCHOICE10 come from the NES 2.0 extended console type byte
+type ConsoleType uint8
+
+const (
+	NESCONSOLE ConsoleType = iota
+	VSSYSTEM
+	PLAYCHOICE10
 )
 
 type Cartridge struct {
-	//what mapper is being used
-	MapperID uint8
+	//name of the game, only known if it was found in a game database
+	Title string
+	//what mapper is being used, 12 bits in NES 2.0 headers, 8 bits in iNES 1.0 headers
+	MapperID uint16
+	//which variant of the mapper is used, only given by NES 2.0 headers, otherwise 0
+	SubmapperID uint8
 	//what type of mirroring the cartridge uses
 	Mirror Mirror
-	//how many banks of memory for each type of data
-	PRGBanks uint8
-	CHRBanks uint8
+	//if the cartridge has battery backed (or otherwise non-volatile) memory
+	Battery bool
+	//how many banks of memory for each type of data, PRG is 16 KB banks, CHR is 8 KB banks
+	PRGBanks uint16
+	CHRBanks uint16
+
+	//sizes of the different types of cartridge memory in bytes
+	PRGROMSize   int
+	CHRROMSize   int
+	PRGRAMSize   int
+	PRGNVRAMSize int
+	CHRRAMSize   int
+	CHRNVRAMSize int
+
+	//if the header is in the NES 2.0 format, if false only the iNES 1.0 fields were available
+	NES20 bool
+	//what CPU/PPU timing the game expects
+	Timing Timing
+	//what console the game was made for
+	ConsoleType ConsoleType
+	//for Vs. System games, the type of PPU and the type of hardware used
+	VsPPUType      uint8
+	VsHardwareType uint8
+	//number of miscellaneous ROMs stored after the CHR data
+	MiscROMs uint8
+	//the default input/expansion device the game expects to be plugged in
+	ExpansionDevice uint8
 
 	//stores the memory
 	PRGMemory []uint8
 	CHRMemory []uint8
+	//if CHRMemory is writable RAM rather than ROM, boards without CHR ROM have RAM the game uploads its tiles into instead
+	CHRRAM bool
+	//work RAM on the cartridge, mapped to CPU 0x6000 - 0x7fff, battery backed if Battery is set
+	PRGRAM []uint8
+	//512 bytes some dumps carry that get loaded into CPU 0x7000 - 0x71ff on power on, nil if there isn't one
+	Trainer []uint8
+	//the extra 2 KB of nametable memory on four-screen boards, used for the nametables at 0x2800 and 0x2c00
+	NameTableRAM [2][1024]uint8
+	//the PPU's own 2 KB of nametable memory, nil until the cartridge is connected to a PPU
+	ciram *[2][1024]uint8
+	//the 1 KB of memory each of the 4 logical nametables uses, rebuilt when something that changes it is written
+	nameTablePages [4][]uint8
+
+	//where the battery backed PRG-RAM is saved, empty if it isn't saved to disk
+	SavePath string
+	//if the PRG-RAM has changed since it was last saved
+	saveDirty bool
+	//the mapper's own battery backed RAM as it was last saved, it's compared since the mapper's RAM isn't written through the cartridge
+	savedMapperRAM []uint8
 
 	//the iNES header
 	Header *iNESHeader
 
 	//the mapper
 	AddressMapper Mapper
+	//the optional interfaces AddressMapper implements
+	hooks mapperHooks
 }
 
 // struct that represents the header of an iNES file
@@ -46,110 +123,393 @@ type iNESHeader struct {
 	chrSize uint8
 	//flags 6, mapper low nybble, mirroring, battery, trainer, 1 byte
 	flag6 byte
-	//flags 7, mapper high nybble, vs/playchoice, NES 1.0, 1 byte
+	//flags 7, mapper high nybble, vs/playchoice, NES 2.0 identifier, 1 byte
 	flag7 byte
-	//flags 8, PRG-RAM size (rarely used extension), 1 byte
+	//flags 8, PRG-RAM size (rarely used extension) in iNES 1.0, mapper high bits and submapper in NES 2.0, 1 byte
 	flag8 byte
-	//flags 9, TV system (rarely used extension), 1 byte
+	//flags 9, TV system (rarely used extension) in iNES 1.0, PRG/CHR ROM size high bits in NES 2.0, 1 byte
 	flag9 byte
-	//flags 10, TV system, PRG-RAM presence (unofficial, rarely used extension), 1 byte
+	//flags 10, TV system, PRG-RAM presence (unofficial, rarely used extension) in iNES 1.0, PRG-RAM/PRG-NVRAM shift counts in NES 2.0, 1 byte
 	flag10 byte
-	//unused, should be either 0s, sometimes people put their name here, 5 bytes
-	pad []byte
+	//the rest of the bytes are only used by NES 2.0, in iNES 1.0 they should be 0s, but sometimes people put their name here
+	//flags 11, CHR-RAM/CHR-NVRAM shift counts, 1 byte
+	flag11 byte
+	//flags 12, CPU/PPU timing, 1 byte
+	flag12 byte
+	//flags 13, Vs. System PPU and hardware type or extended console type, 1 byte
+	flag13 byte
+	//flags 14, number of miscellaneous ROMs, 1 byte
+	flag14 byte
+	//flags 15, default expansion device, 1 byte
+	flag15 byte
 }
 
-func CreateCartridge(filename string) *Cartridge {
-	cart := Cartridge{}
+// checks the identifier bits in flags 7 to see if the header uses the NES 2.0 format
+func (head *iNESHeader) isNES20() bool {
+	return head.flag7&0x0c == 0x08
+}
 
-	//get header data and check if it was succesful
-	header := readHeader(filename)
-	if header == nil {
-		return nil
+// gets the size in bytes of a ROM area in a NES 2.0 header from the low byte in the size fields and the high nybble in flags 9
+// if the high nybble is 0xf the low byte is in exponent-multiplier form, EEEEEEMM, giving a size of 2^E * (MM*2+1)
+func nes20ROMSize(low uint8, high uint8, unit int) int {
+	if high == 0x0f {
+		//anything past 2^30 is far bigger than a real ROM, capping it stops the shift overflowing before the size is rejected
+		exponent := min(low>>2, 30)
+		multiplier := int(low&0x03)*2 + 1
+		return (1 << exponent) * multiplier
 	}
 
-	cart.Header = header
-
-	rom, err := os.Open(filename)
+	return (int(high)<<8 | int(low)) * unit
+}
 
-	//handle potential error in reading file
-	if err != nil {
-		return nil
+// gets the size in bytes of a RAM area in a NES 2.0 header from a shift count, 0 means there is none, otherwise its 64 << shift
+func nes20RAMSize(shift uint8) int {
+	if shift == 0 {
+		return 0
 	}
-	//executed when the surrounding function finishes
-	defer rom.Close()
 
-	//gets the mapper ID from the header data
-	cart.MapperID = (header.flag7 & 0xf0) | (header.flag6 >> 4)
+	return 64 << shift
+}
+
+// fills in the cartridge fields that are described by the header
+func (cart *Cartridge) decodeHeader(header *iNESHeader) {
+	cart.Header = header
+	cart.NES20 = header.isNES20()
+
 	//gets how the cartridge sets up mirroring for the nametable
-	//some mappers may use this header to set the mirroring in a different way, would need to overwrite this in the mapper
-	//TODO make sure mappers can adjust this
-	if header.flag6&0x01 == 0x01 {
+	//mappers that implement MirrorMapper ignore this and set the mirroring themselves, except on four-screen boards
+	if header.flag6&0x08 == 0x08 {
+		cart.Mirror = FOURSCREEN
+	} else if header.flag6&0x01 == 0x01 {
 		cart.Mirror = VERTICAL
 	} else {
 		cart.Mirror = HORIZONTAL
 	}
 
-	//where to begin reading, default is to only skip the first 16 header bytes and begin at byte
-	begin := 16
+	cart.Battery = header.flag6&0x02 == 0x02
+	cart.ConsoleType = ConsoleType(header.flag7 & 0x03)
 
-	//checks if the rom file has trainer data, a depreciated mapping translation used in early NES emulators
-	if header.flag6&4 == 4 {
-		begin += 512
+	if cart.NES20 {
+		//mapper bits 0-7 are in flags 6 and 7, bits 8-11 are the low nybble of flags 8, the high nybble is the submapper
+		cart.MapperID = uint16(header.flag8&0x0f)<<8 | uint16(header.flag7&0xf0) | uint16(header.flag6>>4)
+		cart.SubmapperID = header.flag8 >> 4
+
+		cart.PRGROMSize = nes20ROMSize(header.prgSize, header.flag9&0x0f, 16384)
+		cart.CHRROMSize = nes20ROMSize(header.chrSize, header.flag9>>4, 8192)
+
+		cart.PRGRAMSize = nes20RAMSize(header.flag10 & 0x0f)
+		cart.PRGNVRAMSize = nes20RAMSize(header.flag10 >> 4)
+		cart.CHRRAMSize = nes20RAMSize(header.flag11 & 0x0f)
+		cart.CHRNVRAMSize = nes20RAMSize(header.flag11 >> 4)
+
+		cart.Timing = Timing(header.flag12 & 0x03)
+
+		switch cart.ConsoleType {
+		case VSSYSTEM:
+			cart.VsPPUType = header.flag13 & 0x0f
+			cart.VsHardwareType = header.flag13 >> 4
+		case 3:
+			//extended console type, the numbering carries on from the basic types
+			cart.ConsoleType = ConsoleType(header.flag13 & 0x0f)
+		}
+
+		cart.MiscROMs = header.flag14 & 0x03
+		cart.ExpansionDevice = header.flag15 & 0x3f
+	} else {
+		//some old dumping tools wrote text into the last bytes of the header, which garbles flags 7, so only the low nybble of the mapper can be trusted then
+		if header.flag12 == 0 && header.flag13 == 0 && header.flag14 == 0 && header.flag15 == 0 {
+			cart.MapperID = uint16(header.flag7&0xf0) | uint16(header.flag6>>4)
+		} else {
+			cart.MapperID = uint16(header.flag6 >> 4)
+		}
+
+		cart.PRGROMSize = int(header.prgSize) * 16384
+		cart.CHRROMSize = int(header.chrSize) * 8192
+
+		//flags 8 gives PRG-RAM in 8 KB units, 0 means 8 KB for compatibility with older headers
+		ramSize := int(header.flag8) * 8192
+		if ramSize == 0 {
+			ramSize = 8192
+		}
+		//with a battery the PRG-RAM is non-volatile
+		if cart.Battery {
+			cart.PRGNVRAMSize = ramSize
+		} else {
+			cart.PRGRAMSize = ramSize
+		}
+
+		//no CHR ROM means the board has 8 KB of CHR-RAM instead
+		if cart.CHRROMSize == 0 {
+			cart.CHRRAMSize = 8192
+		}
+
+		if header.flag9&0x01 == 0x01 {
+			cart.Timing = PAL
+		}
 	}
 
-	//there are types 0,1, and 2 of iNES files, but currently just uses type 1
+	//how many full banks there are, rounding up for NES 2.0 sizes that aren't a multiple of the bank size
+	cart.PRGBanks = uint16((cart.PRGROMSize + 16383) / 16384)
+	cart.CHRBanks = uint16((cart.CHRROMSize + 8191) / 8192)
+}
 
-	cart.PRGBanks = header.prgSize
-	cart.CHRBanks = header.chrSize
-	//set the size of the data to the size stated by the header
-	cart.PRGMemory = make([]byte, int(cart.PRGBanks)*16384)
-	cart.CHRMemory = make([]byte, int(cart.PRGBanks)*8192)
+// errors returned when a ROM can't be loaded, checked with errors.Is
+var (
+	ErrBadMagic         = errors.New("not an iNES file, missing the \"NES\" header")
+	ErrTruncatedHeader  = errors.New("header is shorter than 16 bytes")
+	ErrTruncatedTrainer = errors.New("trainer is shorter than 512 bytes")
+	ErrTruncatedPRG     = errors.New("PRG ROM is shorter than the header states")
+	ErrTruncatedCHR     = errors.New("CHR ROM is shorter than the header states")
+	ErrTrailingData     = errors.New("unexpected data after the end of the ROM")
+)
+
+// the largest ROM area accepted, a little over the biggest size the plain NES 2.0 form can state
+const maxROMSize = 64 * 1024 * 1024
 
-	//skip to the program data
-	_, err = rom.Seek(int64(begin), 0)
+// error returned when the header gives a PRG or CHR ROM size that can't be loaded
+type InvalidROMSizeError struct {
+	Area string
+	Size int
+}
+
+func (err *InvalidROMSizeError) Error() string {
+	return fmt.Sprintf("invalid %s ROM size of %d bytes", err.Area, err.Size)
+}
+
+// checks the ROM sizes from the header before anything is allocated for them, every cartridge needs some PRG ROM
+func (cart *Cartridge) checkROMSizes() error {
+	if cart.PRGROMSize <= 0 || cart.PRGROMSize > maxROMSize {
+		return &InvalidROMSizeError{Area: "PRG", Size: cart.PRGROMSize}
+	}
+	if cart.CHRROMSize < 0 || cart.CHRROMSize > maxROMSize {
+		return &InvalidROMSizeError{Area: "CHR", Size: cart.CHRROMSize}
+	}
+
+	return nil
+}
+
+// error returned when the cartridge uses a mapper that hasn't been implemented
+type UnsupportedMapperError struct {
+	MapperID    uint16
+	SubmapperID uint8
+}
+
+func (err *UnsupportedMapperError) Error() string {
+	return fmt.Sprintf("unsupported mapper %d (submapper %d)", err.MapperID, err.SubmapperID)
+}
+
+// opens a ROM file and loads it into a cartridge, along with the .sav file next to it if the cartridge has a battery
+// the ROM can be inside of a .zip or .gz archive
+func CreateCartridge(filename string) (*Cartridge, error) {
+	return CreateCartridgeEntry(filename, "")
+}
+
+// same as CreateCartridge, but entry names which file in a .zip archive to load
+func CreateCartridgeEntry(filename string, entry string) (*Cartridge, error) {
+	data, err := ReadROMEntry(filename, entry)
 	if err != nil {
-		return nil
+		return nil, err
 	}
 
-	//read data into the virtual cartridge
-	_, err = rom.Read(cart.PRGMemory)
+	cart, err := LoadCartridgeBytes(data)
 	if err != nil {
-		return nil
+		return nil, err
+	}
+
+	cart.SavePath = SavePathFor(filename)
+	if err := cart.LoadSave(); err != nil {
+		return nil, err
+	}
+
+	return cart, nil
+}
+
+// gets the path of the .sav file that goes with a ROM file, the same name with the extension swapped
+func SavePathFor(filename string) string {
+	return strings.TrimSuffix(filename, filepath.Ext(filename)) + ".sav"
+}
+
+// loads a cartridge from a ROM image that is already in memory
+func LoadCartridgeBytes(data []byte) (*Cartridge, error) {
+	return LoadCartridge(bytes.NewReader(data))
+}
+
+// same as LoadCartridgeBytes, but the header is corrected from the game database before the mapper is made
+func LoadCartridgeBytesDB(data []byte, db *GameDB) (*Cartridge, error) {
+	return LoadCartridgeDB(bytes.NewReader(data), db)
+}
+
+// loads a cartridge from anything holding an iNES/NES 2.0 or UNIF ROM image, reading until the end of the reader
+func LoadCartridge(reader io.Reader) (*Cartridge, error) {
+	return LoadCartridgeDB(reader, nil)
+}
+
+// same as LoadCartridge, but if the game is in the database its information replaces the header's before the mapper is made
+// so a ROM with a wrong mapper number in its header can still be loaded, db can be nil to trust the header
+func LoadCartridgeDB(reader io.Reader, db *GameDB) (*Cartridge, error) {
+	rom := bufio.NewReader(reader)
+
+	//UNIF files are made of chunks instead of having a fixed header, so they get their own loader
+	if magic, _ := rom.Peek(4); string(magic) == "UNIF" {
+		return loadUNIF(rom, db)
 	}
 
-	_, err = rom.Read(cart.CHRMemory)
+	cart := Cartridge{}
+
+	//get header data and check if it was succesful
+	header, err := readHeader(rom)
 	if err != nil {
-		return nil
+		return nil, err
+	}
+
+	cart.decodeHeader(header)
+	if err := cart.checkROMSizes(); err != nil {
+		return nil, err
+	}
+
+	//checks if the rom file has trainer data, a depreciated mapping translation used in early NES emulators
+	if header.flag6&4 == 4 {
+		cart.Trainer = make([]byte, 512)
+		if err := readSection(rom, cart.Trainer, ErrTruncatedTrainer); err != nil {
+			return nil, err
+		}
+	}
+
+	//set the size of the data to the size stated by the header
+	cart.PRGMemory = make([]byte, cart.PRGROMSize)
+	cart.CHRMemory = make([]byte, cart.CHRROMSize)
+
+	//read data into the virtual cartridge
+	if err := readSection(rom, cart.PRGMemory, ErrTruncatedPRG); err != nil {
+		return nil, err
+	}
+
+	if err := readSection(rom, cart.CHRMemory, ErrTruncatedCHR); err != nil {
+		return nil, err
+	}
+
+	//NES 2.0 sizes that aren't a multiple of the bank size are filled out to whole banks so they can still be mapped
+	cart.PRGMemory = padROM(cart.PRGMemory, int(cart.PRGBanks)*16384)
+	cart.CHRMemory = padROM(cart.CHRMemory, int(cart.CHRBanks)*8192)
+
+	cart.allocateRAM()
+
+	//anything left should only be data the header says is there
+	extra, err := io.Copy(io.Discard, rom)
+	if err != nil {
+		return nil, err
 	}
+	if extra > 0 && !cart.allowsExtraData(extra) {
+		return nil, fmt.Errorf("%w: %d bytes", ErrTrailingData, extra)
+	}
+
+	if db != nil {
+		cart.correctFromGameDB(db)
+	}
+
+	if err := cart.createMapper(); err != nil {
+		return nil, err
+	}
+
+	return &cart, nil
+}
 
-	//sets the type of mapper to be used
-	switch cart.MapperID {
-	case 0:
-		cart.AddressMapper = Mapper000{
-			PRGBanks: cart.PRGBanks,
-			CHRBanks: cart.CHRBanks,
+// sets up the PRG-RAM and, if there's no CHR ROM, the CHR-RAM once the ROM has been read
+func (cart *Cartridge) allocateRAM() {
+	//no CHR ROM means the pattern tables are RAM, 8 KB unless the header says otherwise
+	if cart.CHRROMSize == 0 {
+		ramSize := cart.CHRRAMSize + cart.CHRNVRAMSize
+		if ramSize == 0 {
+			ramSize = 8192
 		}
+		cart.CHRMemory = make([]byte, ramSize)
+		cart.CHRRAM = true
+	}
+
+	cart.PRGRAM = make([]byte, cart.prgRAMSize())
+}
+
+// gets how much PRG-RAM the cartridge should have from the sizes it was given
+func (cart *Cartridge) prgRAMSize() int {
+	//PRG-RAM isn't always listed in headers, so boards are given the common 8 KB when the header doesn't say
+	//the trainer sits at 0x7000, so the RAM needs to cover the full 8 KB for it
+	ramSize := cart.PRGRAMSize + cart.PRGNVRAMSize
+	if ramSize == 0 || (cart.Trainer != nil && ramSize < 8192) {
+		ramSize = 8192
+	}
+
+	return ramSize
+}
+
+// pads ROM data out to size by repeating it, the same as a board with a smaller ROM where the top address lines aren't connected
+func padROM(data []byte, size int) []byte {
+	if len(data) == 0 || len(data) >= size {
+		return data
+	}
+
+	padded := make([]byte, size)
+	for offset := 0; offset < size; offset += len(data) {
+		copy(padded[offset:], data)
 	}
 
-	return &cart
+	return padded
 }
 
-func readHeader(filename string) *iNESHeader {
-	rom, err := os.Open(filename)
+// fills the buffer from the reader, turning a short read into the given error
+func readSection(rom io.Reader, buf []byte, truncated error) error {
+	n, err := io.ReadFull(rom, buf)
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return fmt.Errorf("%w: expected %d bytes, got %d", truncated, len(buf), n)
+	}
+
+	return err
+}
 
-	//handle potential error in reading file
+// checks if data after the CHR ROM is something the header accounts for
+func (cart *Cartridge) allowsExtraData(extra int64) bool {
+	//NES 2.0 stores miscellaneous ROMs at the end of the file, their size isn't given so anything is allowed
+	if cart.NES20 && cart.MiscROMs > 0 {
+		return true
+	}
+	//PlayChoice-10 games have an 8 KB INST-ROM and 32 bytes of PROM data after the CHR ROM
+	if cart.ConsoleType == PLAYCHOICE10 && extra <= 8192+32 {
+		return true
+	}
+
+	return false
+}
+
+// sets the type of mapper to be used, from the mappers in the registry
+func (cart *Cartridge) createMapper() error {
+	constructor, ok := lookupMapper(cart.MapperID, cart.SubmapperID)
+	if !ok {
+		return &UnsupportedMapperError{MapperID: cart.MapperID, SubmapperID: cart.SubmapperID}
+	}
+
+	mapper, err := constructor(cart)
 	if err != nil {
-		fmt.Println(err)
-		return nil
+		return err
 	}
-	//executed when the surrounding function finishes
-	defer rom.Close()
+	cart.AddressMapper = mapper
+	cart.hooks = findMapperHooks(mapper)
+	cart.updateNameTablePages()
+
+	return nil
+}
 
+func readHeader(rom io.Reader) (*iNESHeader, error) {
 	//put the header data into a slice
 	headerData := make([]byte, 16)
-	_, err = rom.Read(headerData)
+	n, err := io.ReadFull(rom, headerData)
+	//a short file that doesn't start like an iNES file is the wrong type of file, not a cut off ROM
+	if !bytes.HasPrefix([]byte("NES\x1a"), headerData[:min(n, 4)]) {
+		return nil, ErrBadMagic
+	}
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return nil, fmt.Errorf("%w: expected %d bytes, got %d", ErrTruncatedHeader, len(headerData), n)
+	}
 	if err != nil {
-		return nil
+		return nil, err
 	}
 
 	head := iNESHeader{}
@@ -163,16 +523,70 @@ func readHeader(filename string) *iNESHeader {
 	head.flag8 = headerData[8]
 	head.flag9 = headerData[9]
 	head.flag10 = headerData[10]
-	head.pad = headerData[11:]
+	head.flag11 = headerData[11]
+	head.flag12 = headerData[12]
+	head.flag13 = headerData[13]
+	head.flag14 = headerData[14]
+	head.flag15 = headerData[15]
+
+	if head.name != "NES\x1a" {
+		return nil, ErrBadMagic
+	}
+
+	return &head, nil
+}
 
-	return &head
+// how many 8 KB banks of CHR memory there are, counting CHR-RAM, which the header gives as 0 CHR banks
+// mapper constructors use it for the size of the CHR memory they bank
+func (cart *Cartridge) CHRMemoryBanks() uint16 {
+	return uint16((len(cart.CHRMemory) + 8191) / 8192)
 }
 
+// if a discrete logic board has bus conflicts, NES 2.0 submapper 1 is without them and 2 is with them
+// otherwise it's what the board usually has, given by usual
+func (cart *Cartridge) busConflicts(usual bool) bool {
+	switch cart.SubmapperID {
+	case 1:
+		return false
+	case 2:
+		return true
+	}
+
+	return usual
+}
+
+// the PRG ROM can't be changed, so writes to it only go to the mapper, which uses them to set its registers
 func (cart *Cartridge) CPUWrite(addr uint16, data uint8) bool {
-	mapAddr, succ := cart.AddressMapper.CPUMapWrite(addr)
-	//if the address was in the cartridge range, write the data and return that it was for the cartridge
+	//the ROM and the CPU both drive the data bus, so the mapper sees the 2 values ANDed together
+	if cart.hooks.busConflicts != nil && addr >= 0x8000 && cart.hooks.busConflicts.BusConflicts() {
+		if romAddr, ok := cart.AddressMapper.CPUMapRead(addr); ok {
+			data &= cart.PRGMemory[romAddr]
+		}
+	}
+
+	_, succ := cart.AddressMapper.CPUMapWrite(addr, data)
+	//if the address was in the cartridge range return that it was for the cartridge
+	//the mapper took the write, so its registers are the only thing that could have moved the nametables
 	if succ {
-		cart.PRGMemory[mapAddr] = data
+		cart.updateNameTablePages()
+		return true
+	}
+
+	//some mappers can put work RAM in the ROM range
+	if cart.hooks.prgRAMBank != nil && addr >= 0x8000 && len(cart.PRGRAM) > 0 {
+		if ramAddr, ok := cart.hooks.prgRAMBank.PRGRAMBankWrite(addr); ok {
+			cart.PRGRAM[ramAddr%uint32(len(cart.PRGRAM))] = data
+			cart.saveDirty = true
+			return true
+		}
+	}
+
+	//work RAM
+	if addr >= 0x6000 && addr <= 0x7fff && len(cart.PRGRAM) > 0 {
+		if ramAddr, ok := cart.prgRAMMapWrite(addr); ok {
+			cart.PRGRAM[ramAddr] = data
+			cart.saveDirty = true
+		}
 		return true
 	}
 
@@ -180,33 +594,229 @@ func (cart *Cartridge) CPUWrite(addr uint16, data uint8) bool {
 }
 
 func (cart *Cartridge) CPURead(addr uint16, readOnly bool) (uint8, bool) {
+	//registers and memory inside the mapper
+	if cart.hooks.cpuData != nil {
+		if data, ok := cart.hooks.cpuData.CPUReadData(addr); ok {
+			return data, true
+		}
+	}
+
 	mapAddr, succ := cart.AddressMapper.CPUMapRead(addr)
 	//if the address was in the cartridge range, return the data and return that it was for the cartridge
 	if succ {
 		return cart.PRGMemory[mapAddr], true
 	}
 
+	if cart.hooks.prgRAMBank != nil && addr >= 0x8000 && len(cart.PRGRAM) > 0 {
+		if ramAddr, ok := cart.hooks.prgRAMBank.PRGRAMBankRead(addr); ok {
+			return cart.PRGRAM[ramAddr%uint32(len(cart.PRGRAM))], true
+		}
+	}
+
+	if addr >= 0x6000 && addr <= 0x7fff && len(cart.PRGRAM) > 0 {
+		if ramAddr, ok := cart.prgRAMMapRead(addr); ok {
+			return cart.PRGRAM[ramAddr], true
+		}
+		//disabled RAM is open bus, which isn't emulated, so it reads as 0
+		return 0x0000, true
+	}
+
 	return 0x0000, false
 }
 
+// connects the PPU's own 2 KB of nametable memory, which the nametable pages are made from
+func (cart *Cartridge) ConnectNameTables(ciram *[2][1024]uint8) {
+	cart.ciram = ciram
+	cart.updateNameTablePages()
+}
+
+// gets the 1 KB of memory each of the 4 logical nametables at 0x2000, 0x2400, 0x2800 and 0x2c00 uses
+// nametables handled by a NameTableMapper never get this far, so whatever page they have here isn't used
+func (cart *Cartridge) NameTablePages() *[4][]uint8 {
+	return &cart.nameTablePages
+}
+
+// rebuilds the nametable pages after the mirroring or the mapper's nametable banks might have changed
+func (cart *Cartridge) updateNameTablePages() {
+	if cart.ciram == nil || cart.AddressMapper == nil {
+		return
+	}
+
+	cart.nameTablePages = cart.findNameTablePages(cart.ciram)
+}
+
+// works out the nametable pages, four-screen boards use their own memory for the last 2 nametables
+func (cart *Cartridge) findNameTablePages(ciram *[2][1024]uint8) [4][]uint8 {
+	if cart.hooks.nameTablePages != nil {
+		return cart.hooks.nameTablePages.NameTablePages(ciram)
+	}
+	if cart.Mirror == FOURSCREEN {
+		return [4][]uint8{ciram[0][:], ciram[1][:], cart.NameTableRAM[0][:], cart.NameTableRAM[1][:]}
+	}
+
+	mirror := cart.GetMirror()
+	var pages [4][]uint8
+	for table := range pages {
+		pages[table] = ciram[mirrorPage(mirror, uint8(table))][:]
+	}
+
+	return pages
+}
+
+// which of the PPU's 2 nametables one of the 4 logical nametables uses in a mirroring layout
+func mirrorPage(mirror Mirror, table uint8) uint8 {
+	switch mirror {
+	case VERTICAL:
+		return table & 1
+	case HORIZONTAL:
+		return table >> 1
+	case ONESCREEN_HI:
+		return 1
+	}
+	return 0
+}
+
+// looks the cartridge up in a game database, and if it's there replaces the header information with the database's
+// returns if the game was found, and an error if the corrected mapper isn't supported
+// the mapper has already been made from the header by then, so LoadCartridgeDB is needed when the header's mapper is unsupported
+func (cart *Cartridge) ApplyGameDB(db *GameDB) (bool, error) {
+	if !cart.correctFromGameDB(db) {
+		return false, nil
+	}
+
+	return true, cart.createMapper()
+}
+
+// replaces the header information with the database's if the cartridge is in it, without making the mapper, returns if it was found
+func (cart *Cartridge) correctFromGameDB(db *GameDB) bool {
+	//the memory is padded out to whole banks, but the database hashes only the ROM data
+	chr := cart.CHRMemory[:cart.CHRROMSize]
+	if cart.CHRRAM {
+		chr = nil
+	}
+
+	info := db.Lookup(cart.PRGMemory[:cart.PRGROMSize], chr)
+	if info == nil {
+		return false
+	}
+
+	cart.Title = info.Title
+	cart.MapperID = info.MapperID
+	cart.SubmapperID = info.SubmapperID
+	cart.Mirror = info.Mirror
+	cart.Battery = info.Battery
+	cart.Timing = info.Timing
+	cart.ConsoleType = info.ConsoleType
+
+	cart.PRGRAMSize = info.PRGRAMSize
+	cart.PRGNVRAMSize = info.PRGNVRAMSize
+	cart.CHRRAMSize = info.CHRRAMSize
+	cart.CHRNVRAMSize = info.CHRNVRAMSize
+
+	//resize the RAM if the database disagrees with the header, following the same rules as the header so a trainer still fits
+	if ramSize := cart.prgRAMSize(); ramSize != len(cart.PRGRAM) {
+		cart.PRGRAM = make([]byte, ramSize)
+	}
+	if ramSize := cart.CHRRAMSize + cart.CHRNVRAMSize; cart.CHRRAM && ramSize != 0 && ramSize != len(cart.CHRMemory) {
+		cart.CHRMemory = make([]byte, ramSize)
+	}
+
+	return true
+}
+
+// puts the cartridge in the state it would be in when the console is turned on, copying the trainer into work RAM at 0x7000
+func (cart *Cartridge) PowerOn() {
+	if cart.Trainer != nil {
+		copy(cart.PRGRAM[0x1000:], cart.Trainer)
+	}
+}
+
+// reads the battery backed PRG-RAM from the save file, a missing save file isn't an error since the game just hasn't saved yet
+func (cart *Cartridge) LoadSave() error {
+	if !cart.Battery || cart.SavePath == "" {
+		return nil
+	}
+
+	cart.savedMapperRAM = bytes.Clone(cart.mapperSaveRAM())
+
+	data, err := os.ReadFile(cart.SavePath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	copy(cart.PRGRAM, data)
+	//the mapper's RAM is saved after the PRG-RAM
+	if mapperRAM := cart.mapperSaveRAM(); mapperRAM != nil && len(data) > len(cart.PRGRAM) {
+		copy(mapperRAM, data[len(cart.PRGRAM):])
+		cart.savedMapperRAM = bytes.Clone(mapperRAM)
+	}
+	cart.saveDirty = false
+	return nil
+}
+
+// writes the battery backed PRG-RAM to the save file if it changed since the last save
+// it's written to a temporary file first so a crash part way through can't destroy the old save
+func (cart *Cartridge) Save() error {
+	mapperRAM := cart.mapperSaveRAM()
+	if !cart.Battery || cart.SavePath == "" || (!cart.saveDirty && bytes.Equal(mapperRAM, cart.savedMapperRAM)) {
+		return nil
+	}
+
+	data := append(bytes.Clone(cart.PRGRAM), mapperRAM...)
+	temp := cart.SavePath + ".tmp"
+	if err := os.WriteFile(temp, data, 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(temp, cart.SavePath); err != nil {
+		return err
+	}
+
+	cart.saveDirty = false
+	cart.savedMapperRAM = bytes.Clone(mapperRAM)
+	return nil
+}
+
 // reads and writes from ppu memory
 func (cart *Cartridge) PPUWrite(addr uint16, data uint8) bool {
+	//pattern memory inside the mapper
+	if cart.hooks.ppuData != nil && addr <= 0x1fff && cart.hooks.ppuData.PPUWriteData(addr, data) {
+		cart.watchPPUAddress(addr)
+		return true
+	}
+
 	mapAddr, succ := cart.AddressMapper.PPUMapWrite(addr)
 	//if the address was in the cartridge range, write the data and return that it was for the cartridge
 	if succ {
 		cart.CHRMemory[mapAddr] = data
-		return true
+	} else if cart.hooks.nameTable != nil && addr >= 0x2000 && addr <= 0x3eff {
+		succ = cart.hooks.nameTable.NameTableWrite(addr, data)
 	}
+	cart.watchPPUAddress(addr)
 
-	return false
+	return succ
 }
 
-func (cart Cartridge) PPURead(addr uint16, readOnly bool) (uint8, bool) {
+func (cart *Cartridge) PPURead(addr uint16, readOnly bool) (uint8, bool) {
+	//pattern memory inside the mapper
+	if cart.hooks.ppuData != nil && addr <= 0x1fff {
+		if data, ok := cart.hooks.ppuData.PPUReadData(addr); ok {
+			cart.watchPPUAddress(addr)
+			return data, true
+		}
+	}
+
 	mapAddr, succ := cart.AddressMapper.PPUMapRead(addr)
 	//if the address was in the cartridge range, return the data and return that it was for the cartridge
+	data := uint8(0x0000)
 	if succ {
-		return cart.CHRMemory[mapAddr], true
+		data = cart.CHRMemory[mapAddr]
+	} else if cart.hooks.nameTable != nil && addr >= 0x2000 && addr <= 0x3eff {
+		data, succ = cart.hooks.nameTable.NameTableRead(addr)
 	}
+	cart.watchPPUAddress(addr)
 
-	return 0x0000, false
+	return data, succ
 }
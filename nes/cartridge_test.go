@@ -0,0 +1,33 @@
+package nes
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestNameTablePagesFollowMapperWrites(t *testing.T) {
+	//an AxROM cartridge, mapper 7, with 32 KB of PRG ROM filled with 0xff so bus conflicts don't change the writes
+	rom := append([]byte("NES\x1a\x02\x00\x70\x00"), make([]byte, 8)...)
+	rom = append(rom, bytes.Repeat([]byte{0xff}, 0x8000)...)
+	cart, err := LoadCartridgeBytes(rom)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ciram := [2][1024]uint8{}
+	cart.ConnectNameTables(&ciram)
+
+	//selecting the second nametable moves all 4 pages to it
+	cart.CPUWrite(0x8000, 0x10)
+	for i, page := range cart.NameTablePages() {
+		if &page[0] != &ciram[1][0] {
+			t.Errorf("nametable %d isn't in the second page after the register write", i)
+		}
+	}
+
+	//a store to work RAM isn't a register write, so the pages aren't rebuilt
+	cart.nameTablePages[0] = nil
+	cart.CPUWrite(0x6000, 0x00)
+	if cart.NameTablePages()[0] != nil {
+		t.Error("the nametable pages were rebuilt after a work RAM write")
+	}
+}
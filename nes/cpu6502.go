@@ -40,6 +40,8 @@ type CPU6502 struct {
 	opCode uint8
 	//cycles for the current instruction
 	cycles uint8
+	//if a KIL opcode stopped the cpu, only a reset starts it again
+	halted bool
 	//lookup table of instruction structs, the index in the table is the numerical value of the instruction
 	instructions [256]Instruction
 }
@@ -56,6 +58,8 @@ type Instruction struct {
 	modeType string
 	addrMode addressingMode
 	cycles   uint8
+	//if the cycles already include fixing the address when indexing crosses a page, writes always take that cycle so only reads can add it
+	fixedCycles bool
 }
 
 // constructor to create a cpu so it initializes the lookup table
@@ -63,22 +67,22 @@ func CreateCPU() *CPU6502 {
 	cpu := CPU6502{}
 	//ugly, gross, disgusting, bad, not good, but it initializes the entire table
 	cpu.instructions = [256]Instruction{
-		{name: "BRK", op: BRK, modeType: "IMP", addrMode: IMP, cycles: 7}, {name: "ORA", op: ORA, modeType: "IDX", addrMode: IDX, cycles: 6}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6}, {name: "ORA", op: ORA, modeType: "ZPI", addrMode: ZPI, cycles: 3}, {name: "ASL", op: ASL, modeType: "ZPI", addrMode: ZPI, cycles: 5}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6}, {name: "PHP", op: PHP, modeType: "IMP", addrMode: IMP, cycles: 3}, {name: "ORA", op: ORA, modeType: "IMM", addrMode: IMM, cycles: 2}, {name: "ASL", op: ASL, modeType: "ACC", addrMode: ACC, cycles: 2}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6}, {name: "ORA", op: ORA, modeType: "ABS", addrMode: ABS, cycles: 4}, {name: "ASL", op: ASL, modeType: "ABS", addrMode: ABS, cycles: 6}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6},
-		{name: "BPL", op: BPL, modeType: "REL", addrMode: REL, cycles: 2}, {name: "ORA", op: ORA, modeType: "IDY", addrMode: IDY, cycles: 5}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6}, {name: "ORA", op: ORA, modeType: "ZPX", addrMode: ZPX, cycles: 4}, {name: "ASL", op: ASL, modeType: "ZPX", addrMode: ZPX, cycles: 6}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6}, {name: "CLC", op: CLC, modeType: "IMP", addrMode: IMP, cycles: 2}, {name: "ORA", op: ORA, modeType: "ABY", addrMode: ABY, cycles: 4}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6}, {name: "ORA", op: ORA, modeType: "ABX", addrMode: ABX, cycles: 4}, {name: "ASL", op: ASL, modeType: "ABX", addrMode: ABX, cycles: 7}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6},
-		{name: "JSR", op: JSR, modeType: "ABS", addrMode: ABS, cycles: 6}, {name: "AND", op: AND, modeType: "IDX", addrMode: IDX, cycles: 6}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6}, {name: "BIT", op: BIT, modeType: "ZPI", addrMode: ZPI, cycles: 3}, {name: "AND", op: AND, modeType: "ZPI", addrMode: ZPI, cycles: 3}, {name: "ROL", op: ROL, modeType: "ZPI", addrMode: ZPI, cycles: 5}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6}, {name: "PLP", op: PLP, modeType: "IMP", addrMode: IMP, cycles: 4}, {name: "AND", op: AND, modeType: "IMM", addrMode: IMM, cycles: 2}, {name: "ROL", op: ROL, modeType: "ACC", addrMode: ACC, cycles: 2}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6}, {name: "BIT", op: BIT, modeType: "ABS", addrMode: ABS, cycles: 4}, {name: "AND", op: AND, modeType: "ABS", addrMode: ABS, cycles: 4}, {name: "ROL", op: ROL, modeType: "ABS", addrMode: ABS, cycles: 6}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6},
-		{name: "BMI", op: BMI, modeType: "REL", addrMode: REL, cycles: 2}, {name: "AND", op: AND, modeType: "IDY", addrMode: IDY, cycles: 5}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6}, {name: "AND", op: AND, modeType: "ZPX", addrMode: ZPX, cycles: 4}, {name: "ROL", op: ROL, modeType: "ZPX", addrMode: ZPX, cycles: 6}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6}, {name: "SEC", op: SEC, modeType: "IMP", addrMode: IMP, cycles: 2}, {name: "AND", op: AND, modeType: "ABY", addrMode: ABY, cycles: 4}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6}, {name: "AND", op: AND, modeType: "ABX", addrMode: ABX, cycles: 4}, {name: "ROL", op: ROL, modeType: "ABX", addrMode: ABX, cycles: 7}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6},
-		{name: "RTI", op: RTI, modeType: "IMP", addrMode: IMP, cycles: 6}, {name: "EOR", op: EOR, modeType: "IDX", addrMode: IDX, cycles: 6}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6}, {name: "EOR", op: EOR, modeType: "ZPI", addrMode: ZPI, cycles: 3}, {name: "LSR", op: LSR, modeType: "ZPI", addrMode: ZPI, cycles: 5}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6}, {name: "PHA", op: PHA, modeType: "IMP", addrMode: IMP, cycles: 3}, {name: "EOR", op: EOR, modeType: "IMM", addrMode: IMM, cycles: 2}, {name: "LSR", op: LSR, modeType: "ACC", addrMode: ACC, cycles: 2}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6}, {name: "JMP", op: JMP, modeType: "ABS", addrMode: ABS, cycles: 3}, {name: "EOR", op: EOR, modeType: "ABS", addrMode: ABS, cycles: 4}, {name: "LSR", op: LSR, modeType: "ABS", addrMode: ABS, cycles: 6}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6},
-		{name: "BVC", op: BVC, modeType: "REL", addrMode: REL, cycles: 2}, {name: "EOR", op: EOR, modeType: "IDY", addrMode: IDY, cycles: 5}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6}, {name: "EOR", op: EOR, modeType: "ZPX", addrMode: ZPX, cycles: 4}, {name: "LSR", op: LSR, modeType: "ZPX", addrMode: ZPX, cycles: 6}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6}, {name: "CLI", op: CLI, modeType: "IMP", addrMode: IMP, cycles: 2}, {name: "EOR", op: EOR, modeType: "ABY", addrMode: ABY, cycles: 4}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6}, {name: "EOR", op: EOR, modeType: "ABX", addrMode: ABX, cycles: 4}, {name: "LSR", op: LSR, modeType: "ABX", addrMode: ABX, cycles: 7}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6},
-		{name: "RTS", op: RTS, modeType: "IMP", addrMode: IMP, cycles: 6}, {name: "ADC", op: ADC, modeType: "IDX", addrMode: IDX, cycles: 6}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6}, {name: "ADC", op: ADC, modeType: "ZPI", addrMode: ZPI, cycles: 3}, {name: "ROR", op: ROR, modeType: "ZPI", addrMode: ZPI, cycles: 5}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6}, {name: "PLA", op: PLA, modeType: "IMP", addrMode: IMP, cycles: 4}, {name: "ADC", op: ADC, modeType: "IMM", addrMode: IMM, cycles: 2}, {name: "ROR", op: ROR, modeType: "ACC", addrMode: ACC, cycles: 2}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6}, {name: "JMP", op: JMP, modeType: "IND", addrMode: IND, cycles: 5}, {name: "ADC", op: ADC, modeType: "ABS", addrMode: ABS, cycles: 4}, {name: "ROR", op: ROR, modeType: "ABS", addrMode: ABS, cycles: 6}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6},
-		{name: "BVS", op: BVS, modeType: "REL", addrMode: REL, cycles: 2}, {name: "ADC", op: ADC, modeType: "IDY", addrMode: IDY, cycles: 5}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6}, {name: "ADC", op: ADC, modeType: "ZPX", addrMode: ZPX, cycles: 4}, {name: "ROR", op: ROR, modeType: "ZPX", addrMode: ZPX, cycles: 6}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6}, {name: "SEI", op: SEI, modeType: "IMP", addrMode: IMP, cycles: 2}, {name: "ADC", op: ADC, modeType: "ABY", addrMode: ABY, cycles: 4}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6}, {name: "ADC", op: ADC, modeType: "ABX", addrMode: ABX, cycles: 4}, {name: "ROR", op: ROR, modeType: "ABX", addrMode: ABX, cycles: 7}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6},
-		{name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6}, {name: "STA", op: STA, modeType: "IDX", addrMode: IDX, cycles: 6}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6}, {name: "STY", op: STY, modeType: "ZPI", addrMode: ZPI, cycles: 3}, {name: "STA", op: STA, modeType: "ZPI", addrMode: ZPI, cycles: 3}, {name: "STX", op: STX, modeType: "ZPI", addrMode: ZPI, cycles: 3}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6}, {name: "DEY", op: DEY, modeType: "IMP", addrMode: IMP, cycles: 2}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6}, {name: "TXA", op: TXA, modeType: "IMP", addrMode: IMP, cycles: 2}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6}, {name: "STY", op: STY, modeType: "ABS", addrMode: ABS, cycles: 4}, {name: "STA", op: STA, modeType: "ABS", addrMode: ABS, cycles: 4}, {name: "STX", op: STX, modeType: "ABS", addrMode: ABS, cycles: 4}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6},
-		{name: "BCC", op: BCC, modeType: "REL", addrMode: REL, cycles: 2}, {name: "STA", op: STA, modeType: "IDY", addrMode: IDY, cycles: 6}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6}, {name: "STY", op: STY, modeType: "ZPX", addrMode: ZPX, cycles: 4}, {name: "STA", op: STA, modeType: "ZPX", addrMode: ZPX, cycles: 4}, {name: "STX", op: STX, modeType: "ZPY", addrMode: ZPY, cycles: 4}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6}, {name: "TYA", op: TYA, modeType: "IMP", addrMode: IMP, cycles: 2}, {name: "STA", op: STA, modeType: "ABY", addrMode: ABY, cycles: 5}, {name: "TXS", op: TXS, modeType: "IMP", addrMode: IMP, cycles: 2}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6}, {name: "STA", op: STA, modeType: "ABX", addrMode: ABX, cycles: 5}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6},
-		{name: "LDY", op: LDY, modeType: "IMM", addrMode: IMM, cycles: 2}, {name: "LDA", op: LDA, modeType: "IDX", addrMode: IDX, cycles: 6}, {name: "LDX", op: LDX, modeType: "IMM", addrMode: IMM, cycles: 2}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6}, {name: "LDY", op: LDY, modeType: "ZPI", addrMode: ZPI, cycles: 3}, {name: "LDA", op: LDA, modeType: "ZPI", addrMode: ZPI, cycles: 3}, {name: "LDX", op: LDX, modeType: "ZPI", addrMode: ZPI, cycles: 3}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6}, {name: "TAY", op: TAY, modeType: "IMP", addrMode: IMP, cycles: 2}, {name: "LDA", op: LDA, modeType: "IMM", addrMode: IMM, cycles: 2}, {name: "TAX", op: TAX, modeType: "IMP", addrMode: IMP, cycles: 2}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6}, {name: "LDY", op: LDY, modeType: "ABS", addrMode: ABS, cycles: 4}, {name: "LDA", op: LDA, modeType: "ABS", addrMode: ABS, cycles: 4}, {name: "LDX", op: LDX, modeType: "ABS", addrMode: ABS, cycles: 4}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6},
-		{name: "BCS", op: BCS, modeType: "REL", addrMode: REL, cycles: 2}, {name: "LDA", op: LDA, modeType: "IDY", addrMode: IDY, cycles: 5}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6}, {name: "LDY", op: LDY, modeType: "ZPX", addrMode: ZPX, cycles: 4}, {name: "LDA", op: LDA, modeType: "ZPX", addrMode: ZPX, cycles: 4}, {name: "LDX", op: LDX, modeType: "ZPY", addrMode: ZPY, cycles: 4}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6}, {name: "CLV", op: CLV, modeType: "IMP", addrMode: IMP, cycles: 2}, {name: "LDA", op: LDA, modeType: "ABY", addrMode: ABY, cycles: 4}, {name: "TSX", op: TSX, modeType: "IMP", addrMode: IMP, cycles: 2}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6}, {name: "LDY", op: LDY, modeType: "ABX", addrMode: ABX, cycles: 4}, {name: "LDA", op: LDA, modeType: "ABX", addrMode: ABX, cycles: 4}, {name: "LDX", op: LDX, modeType: "ABY", addrMode: ABY, cycles: 4}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6},
-		{name: "CPY", op: CPY, modeType: "IMM", addrMode: IMM, cycles: 2}, {name: "CMP", op: CMP, modeType: "IDX", addrMode: IDX, cycles: 6}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6}, {name: "CPY", op: CPY, modeType: "ZPI", addrMode: ZPI, cycles: 3}, {name: "CMP", op: CMP, modeType: "ZPI", addrMode: ZPI, cycles: 3}, {name: "DEC", op: DEC, modeType: "ZPI", addrMode: ZPI, cycles: 5}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6}, {name: "INY", op: INY, modeType: "IMP", addrMode: IMP, cycles: 2}, {name: "CMP", op: CMP, modeType: "IMM", addrMode: IMM, cycles: 2}, {name: "DEX", op: DEX, modeType: "IMP", addrMode: IMP, cycles: 2}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6}, {name: "CPY", op: CPY, modeType: "ABS", addrMode: ABS, cycles: 4}, {name: "CMP", op: CMP, modeType: "ABS", addrMode: ABS, cycles: 4}, {name: "DEC", op: DEC, modeType: "ABS", addrMode: ABS, cycles: 6}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6},
-		{name: "BNE", op: BNE, modeType: "REL", addrMode: REL, cycles: 2}, {name: "CMP", op: CMP, modeType: "IDY", addrMode: IDY, cycles: 5}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6}, {name: "CMP", op: CMP, modeType: "ZPX", addrMode: ZPX, cycles: 4}, {name: "DEC", op: DEC, modeType: "ZPX", addrMode: ZPX, cycles: 6}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6}, {name: "CLD", op: CLD, modeType: "IMP", addrMode: IMP, cycles: 2}, {name: "CMP", op: CMP, modeType: "ABY", addrMode: ABY, cycles: 4}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6}, {name: "CMP", op: CMP, modeType: "ABX", addrMode: ABX, cycles: 4}, {name: "DEC", op: DEC, modeType: "ABX", addrMode: ABX, cycles: 7}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6},
-		{name: "CPX", op: CPX, modeType: "IMM", addrMode: IMM, cycles: 2}, {name: "SBC", op: SBC, modeType: "IDX", addrMode: IDX, cycles: 6}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6}, {name: "CPX", op: CPX, modeType: "ZPI", addrMode: ZPI, cycles: 3}, {name: "SBC", op: SBC, modeType: "ZPI", addrMode: ZPI, cycles: 3}, {name: "INC", op: INC, modeType: "ZPI", addrMode: ZPI, cycles: 5}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6}, {name: "INX", op: INX, modeType: "IMP", addrMode: IMP, cycles: 2}, {name: "SBC", op: SBC, modeType: "IMM", addrMode: IMM, cycles: 2}, {name: "NOP", op: NOP, modeType: "IMP", addrMode: IMP, cycles: 2}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6}, {name: "CPX", op: CPX, modeType: "ABS", addrMode: ABS, cycles: 4}, {name: "SBC", op: SBC, modeType: "ABS", addrMode: ABS, cycles: 4}, {name: "INC", op: INC, modeType: "ABS", addrMode: ABS, cycles: 6}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6},
-		{name: "BEQ", op: BEQ, modeType: "REL", addrMode: REL, cycles: 2}, {name: "SBC", op: SBC, modeType: "IDY", addrMode: IDY, cycles: 5}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6}, {name: "SBC", op: SBC, modeType: "ZPX", addrMode: ZPX, cycles: 4}, {name: "INC", op: INC, modeType: "ZPX", addrMode: ZPX, cycles: 6}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6}, {name: "SED", op: SED, modeType: "IMP", addrMode: IMP, cycles: 2}, {name: "SBC", op: SBC, modeType: "ABY", addrMode: ABY, cycles: 4}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6}, {name: "SBC", op: SBC, modeType: "ABX", addrMode: ABX, cycles: 4}, {name: "INC", op: INC, modeType: "ABX", addrMode: ABX, cycles: 7}, {name: "NEX", op: NEX, modeType: "IMP", addrMode: IMP, cycles: 6},
+		{name: "BRK", op: BRK, modeType: "IMP", addrMode: IMP, cycles: 7}, {name: "ORA", op: ORA, modeType: "IDX", addrMode: IDX, cycles: 6}, {name: "KIL", op: KIL, modeType: "IMP", addrMode: IMP, cycles: 2}, {name: "SLO", op: SLO, modeType: "IDX", addrMode: IDX, cycles: 8}, {name: "NOP", op: NOP, modeType: "ZPI", addrMode: ZPI, cycles: 3}, {name: "ORA", op: ORA, modeType: "ZPI", addrMode: ZPI, cycles: 3}, {name: "ASL", op: ASL, modeType: "ZPI", addrMode: ZPI, cycles: 5}, {name: "SLO", op: SLO, modeType: "ZPI", addrMode: ZPI, cycles: 5}, {name: "PHP", op: PHP, modeType: "IMP", addrMode: IMP, cycles: 3}, {name: "ORA", op: ORA, modeType: "IMM", addrMode: IMM, cycles: 2}, {name: "ASL", op: ASL, modeType: "ACC", addrMode: ACC, cycles: 2}, {name: "ANC", op: ANC, modeType: "IMM", addrMode: IMM, cycles: 2}, {name: "NOP", op: NOP, modeType: "ABS", addrMode: ABS, cycles: 4}, {name: "ORA", op: ORA, modeType: "ABS", addrMode: ABS, cycles: 4}, {name: "ASL", op: ASL, modeType: "ABS", addrMode: ABS, cycles: 6}, {name: "SLO", op: SLO, modeType: "ABS", addrMode: ABS, cycles: 6},
+		{name: "BPL", op: BPL, modeType: "REL", addrMode: REL, cycles: 2}, {name: "ORA", op: ORA, modeType: "IDY", addrMode: IDY, cycles: 5}, {name: "KIL", op: KIL, modeType: "IMP", addrMode: IMP, cycles: 2}, {name: "SLO", op: SLO, modeType: "IDY", addrMode: IDY, cycles: 8, fixedCycles: true}, {name: "NOP", op: NOP, modeType: "ZPX", addrMode: ZPX, cycles: 4}, {name: "ORA", op: ORA, modeType: "ZPX", addrMode: ZPX, cycles: 4}, {name: "ASL", op: ASL, modeType: "ZPX", addrMode: ZPX, cycles: 6}, {name: "SLO", op: SLO, modeType: "ZPX", addrMode: ZPX, cycles: 6}, {name: "CLC", op: CLC, modeType: "IMP", addrMode: IMP, cycles: 2}, {name: "ORA", op: ORA, modeType: "ABY", addrMode: ABY, cycles: 4}, {name: "NOP", op: NOP, modeType: "IMP", addrMode: IMP, cycles: 2}, {name: "SLO", op: SLO, modeType: "ABY", addrMode: ABY, cycles: 7, fixedCycles: true}, {name: "NOP", op: NOP, modeType: "ABX", addrMode: ABX, cycles: 4}, {name: "ORA", op: ORA, modeType: "ABX", addrMode: ABX, cycles: 4}, {name: "ASL", op: ASL, modeType: "ABX", addrMode: ABX, cycles: 7, fixedCycles: true}, {name: "SLO", op: SLO, modeType: "ABX", addrMode: ABX, cycles: 7, fixedCycles: true},
+		{name: "JSR", op: JSR, modeType: "ABS", addrMode: ABS, cycles: 6}, {name: "AND", op: AND, modeType: "IDX", addrMode: IDX, cycles: 6}, {name: "KIL", op: KIL, modeType: "IMP", addrMode: IMP, cycles: 2}, {name: "RLA", op: RLA, modeType: "IDX", addrMode: IDX, cycles: 8}, {name: "BIT", op: BIT, modeType: "ZPI", addrMode: ZPI, cycles: 3}, {name: "AND", op: AND, modeType: "ZPI", addrMode: ZPI, cycles: 3}, {name: "ROL", op: ROL, modeType: "ZPI", addrMode: ZPI, cycles: 5}, {name: "RLA", op: RLA, modeType: "ZPI", addrMode: ZPI, cycles: 5}, {name: "PLP", op: PLP, modeType: "IMP", addrMode: IMP, cycles: 4}, {name: "AND", op: AND, modeType: "IMM", addrMode: IMM, cycles: 2}, {name: "ROL", op: ROL, modeType: "ACC", addrMode: ACC, cycles: 2}, {name: "ANC", op: ANC, modeType: "IMM", addrMode: IMM, cycles: 2}, {name: "BIT", op: BIT, modeType: "ABS", addrMode: ABS, cycles: 4}, {name: "AND", op: AND, modeType: "ABS", addrMode: ABS, cycles: 4}, {name: "ROL", op: ROL, modeType: "ABS", addrMode: ABS, cycles: 6}, {name: "RLA", op: RLA, modeType: "ABS", addrMode: ABS, cycles: 6},
+		{name: "BMI", op: BMI, modeType: "REL", addrMode: REL, cycles: 2}, {name: "AND", op: AND, modeType: "IDY", addrMode: IDY, cycles: 5}, {name: "KIL", op: KIL, modeType: "IMP", addrMode: IMP, cycles: 2}, {name: "RLA", op: RLA, modeType: "IDY", addrMode: IDY, cycles: 8, fixedCycles: true}, {name: "NOP", op: NOP, modeType: "ZPX", addrMode: ZPX, cycles: 4}, {name: "AND", op: AND, modeType: "ZPX", addrMode: ZPX, cycles: 4}, {name: "ROL", op: ROL, modeType: "ZPX", addrMode: ZPX, cycles: 6}, {name: "RLA", op: RLA, modeType: "ZPX", addrMode: ZPX, cycles: 6}, {name: "SEC", op: SEC, modeType: "IMP", addrMode: IMP, cycles: 2}, {name: "AND", op: AND, modeType: "ABY", addrMode: ABY, cycles: 4}, {name: "NOP", op: NOP, modeType: "IMP", addrMode: IMP, cycles: 2}, {name: "RLA", op: RLA, modeType: "ABY", addrMode: ABY, cycles: 7, fixedCycles: true}, {name: "NOP", op: NOP, modeType: "ABX", addrMode: ABX, cycles: 4}, {name: "AND", op: AND, modeType: "ABX", addrMode: ABX, cycles: 4}, {name: "ROL", op: ROL, modeType: "ABX", addrMode: ABX, cycles: 7, fixedCycles: true}, {name: "RLA", op: RLA, modeType: "ABX", addrMode: ABX, cycles: 7, fixedCycles: true},
+		{name: "RTI", op: RTI, modeType: "IMP", addrMode: IMP, cycles: 6}, {name: "EOR", op: EOR, modeType: "IDX", addrMode: IDX, cycles: 6}, {name: "KIL", op: KIL, modeType: "IMP", addrMode: IMP, cycles: 2}, {name: "SRE", op: SRE, modeType: "IDX", addrMode: IDX, cycles: 8}, {name: "NOP", op: NOP, modeType: "ZPI", addrMode: ZPI, cycles: 3}, {name: "EOR", op: EOR, modeType: "ZPI", addrMode: ZPI, cycles: 3}, {name: "LSR", op: LSR, modeType: "ZPI", addrMode: ZPI, cycles: 5}, {name: "SRE", op: SRE, modeType: "ZPI", addrMode: ZPI, cycles: 5}, {name: "PHA", op: PHA, modeType: "IMP", addrMode: IMP, cycles: 3}, {name: "EOR", op: EOR, modeType: "IMM", addrMode: IMM, cycles: 2}, {name: "LSR", op: LSR, modeType: "ACC", addrMode: ACC, cycles: 2}, {name: "ALR", op: ALR, modeType: "IMM", addrMode: IMM, cycles: 2}, {name: "JMP", op: JMP, modeType: "ABS", addrMode: ABS, cycles: 3}, {name: "EOR", op: EOR, modeType: "ABS", addrMode: ABS, cycles: 4}, {name: "LSR", op: LSR, modeType: "ABS", addrMode: ABS, cycles: 6}, {name: "SRE", op: SRE, modeType: "ABS", addrMode: ABS, cycles: 6},
+		{name: "BVC", op: BVC, modeType: "REL", addrMode: REL, cycles: 2}, {name: "EOR", op: EOR, modeType: "IDY", addrMode: IDY, cycles: 5}, {name: "KIL", op: KIL, modeType: "IMP", addrMode: IMP, cycles: 2}, {name: "SRE", op: SRE, modeType: "IDY", addrMode: IDY, cycles: 8, fixedCycles: true}, {name: "NOP", op: NOP, modeType: "ZPX", addrMode: ZPX, cycles: 4}, {name: "EOR", op: EOR, modeType: "ZPX", addrMode: ZPX, cycles: 4}, {name: "LSR", op: LSR, modeType: "ZPX", addrMode: ZPX, cycles: 6}, {name: "SRE", op: SRE, modeType: "ZPX", addrMode: ZPX, cycles: 6}, {name: "CLI", op: CLI, modeType: "IMP", addrMode: IMP, cycles: 2}, {name: "EOR", op: EOR, modeType: "ABY", addrMode: ABY, cycles: 4}, {name: "NOP", op: NOP, modeType: "IMP", addrMode: IMP, cycles: 2}, {name: "SRE", op: SRE, modeType: "ABY", addrMode: ABY, cycles: 7, fixedCycles: true}, {name: "NOP", op: NOP, modeType: "ABX", addrMode: ABX, cycles: 4}, {name: "EOR", op: EOR, modeType: "ABX", addrMode: ABX, cycles: 4}, {name: "LSR", op: LSR, modeType: "ABX", addrMode: ABX, cycles: 7, fixedCycles: true}, {name: "SRE", op: SRE, modeType: "ABX", addrMode: ABX, cycles: 7, fixedCycles: true},
+		{name: "RTS", op: RTS, modeType: "IMP", addrMode: IMP, cycles: 6}, {name: "ADC", op: ADC, modeType: "IDX", addrMode: IDX, cycles: 6}, {name: "KIL", op: KIL, modeType: "IMP", addrMode: IMP, cycles: 2}, {name: "RRA", op: RRA, modeType: "IDX", addrMode: IDX, cycles: 8}, {name: "NOP", op: NOP, modeType: "ZPI", addrMode: ZPI, cycles: 3}, {name: "ADC", op: ADC, modeType: "ZPI", addrMode: ZPI, cycles: 3}, {name: "ROR", op: ROR, modeType: "ZPI", addrMode: ZPI, cycles: 5}, {name: "RRA", op: RRA, modeType: "ZPI", addrMode: ZPI, cycles: 5}, {name: "PLA", op: PLA, modeType: "IMP", addrMode: IMP, cycles: 4}, {name: "ADC", op: ADC, modeType: "IMM", addrMode: IMM, cycles: 2}, {name: "ROR", op: ROR, modeType: "ACC", addrMode: ACC, cycles: 2}, {name: "ARR", op: ARR, modeType: "IMM", addrMode: IMM, cycles: 2}, {name: "JMP", op: JMP, modeType: "IND", addrMode: IND, cycles: 5}, {name: "ADC", op: ADC, modeType: "ABS", addrMode: ABS, cycles: 4}, {name: "ROR", op: ROR, modeType: "ABS", addrMode: ABS, cycles: 6}, {name: "RRA", op: RRA, modeType: "ABS", addrMode: ABS, cycles: 6},
+		{name: "BVS", op: BVS, modeType: "REL", addrMode: REL, cycles: 2}, {name: "ADC", op: ADC, modeType: "IDY", addrMode: IDY, cycles: 5}, {name: "KIL", op: KIL, modeType: "IMP", addrMode: IMP, cycles: 2}, {name: "RRA", op: RRA, modeType: "IDY", addrMode: IDY, cycles: 8, fixedCycles: true}, {name: "NOP", op: NOP, modeType: "ZPX", addrMode: ZPX, cycles: 4}, {name: "ADC", op: ADC, modeType: "ZPX", addrMode: ZPX, cycles: 4}, {name: "ROR", op: ROR, modeType: "ZPX", addrMode: ZPX, cycles: 6}, {name: "RRA", op: RRA, modeType: "ZPX", addrMode: ZPX, cycles: 6}, {name: "SEI", op: SEI, modeType: "IMP", addrMode: IMP, cycles: 2}, {name: "ADC", op: ADC, modeType: "ABY", addrMode: ABY, cycles: 4}, {name: "NOP", op: NOP, modeType: "IMP", addrMode: IMP, cycles: 2}, {name: "RRA", op: RRA, modeType: "ABY", addrMode: ABY, cycles: 7, fixedCycles: true}, {name: "NOP", op: NOP, modeType: "ABX", addrMode: ABX, cycles: 4}, {name: "ADC", op: ADC, modeType: "ABX", addrMode: ABX, cycles: 4}, {name: "ROR", op: ROR, modeType: "ABX", addrMode: ABX, cycles: 7, fixedCycles: true}, {name: "RRA", op: RRA, modeType: "ABX", addrMode: ABX, cycles: 7, fixedCycles: true},
+		{name: "NOP", op: NOP, modeType: "IMM", addrMode: IMM, cycles: 2}, {name: "STA", op: STA, modeType: "IDX", addrMode: IDX, cycles: 6}, {name: "NOP", op: NOP, modeType: "IMM", addrMode: IMM, cycles: 2}, {name: "SAX", op: SAX, modeType: "IDX", addrMode: IDX, cycles: 6}, {name: "STY", op: STY, modeType: "ZPI", addrMode: ZPI, cycles: 3}, {name: "STA", op: STA, modeType: "ZPI", addrMode: ZPI, cycles: 3}, {name: "STX", op: STX, modeType: "ZPI", addrMode: ZPI, cycles: 3}, {name: "SAX", op: SAX, modeType: "ZPI", addrMode: ZPI, cycles: 3}, {name: "DEY", op: DEY, modeType: "IMP", addrMode: IMP, cycles: 2}, {name: "NOP", op: NOP, modeType: "IMM", addrMode: IMM, cycles: 2}, {name: "TXA", op: TXA, modeType: "IMP", addrMode: IMP, cycles: 2}, {name: "XAA", op: XAA, modeType: "IMM", addrMode: IMM, cycles: 2}, {name: "STY", op: STY, modeType: "ABS", addrMode: ABS, cycles: 4}, {name: "STA", op: STA, modeType: "ABS", addrMode: ABS, cycles: 4}, {name: "STX", op: STX, modeType: "ABS", addrMode: ABS, cycles: 4}, {name: "SAX", op: SAX, modeType: "ABS", addrMode: ABS, cycles: 4},
+		{name: "BCC", op: BCC, modeType: "REL", addrMode: REL, cycles: 2}, {name: "STA", op: STA, modeType: "IDY", addrMode: IDY, cycles: 6, fixedCycles: true}, {name: "KIL", op: KIL, modeType: "IMP", addrMode: IMP, cycles: 2}, {name: "SHA", op: SHA, modeType: "IDY", addrMode: IDY, cycles: 6, fixedCycles: true}, {name: "STY", op: STY, modeType: "ZPX", addrMode: ZPX, cycles: 4}, {name: "STA", op: STA, modeType: "ZPX", addrMode: ZPX, cycles: 4}, {name: "STX", op: STX, modeType: "ZPY", addrMode: ZPY, cycles: 4}, {name: "SAX", op: SAX, modeType: "ZPY", addrMode: ZPY, cycles: 4}, {name: "TYA", op: TYA, modeType: "IMP", addrMode: IMP, cycles: 2}, {name: "STA", op: STA, modeType: "ABY", addrMode: ABY, cycles: 5, fixedCycles: true}, {name: "TXS", op: TXS, modeType: "IMP", addrMode: IMP, cycles: 2}, {name: "TAS", op: TAS, modeType: "ABY", addrMode: ABY, cycles: 5, fixedCycles: true}, {name: "SHY", op: SHY, modeType: "ABX", addrMode: ABX, cycles: 5, fixedCycles: true}, {name: "STA", op: STA, modeType: "ABX", addrMode: ABX, cycles: 5, fixedCycles: true}, {name: "SHX", op: SHX, modeType: "ABY", addrMode: ABY, cycles: 5, fixedCycles: true}, {name: "SHA", op: SHA, modeType: "ABY", addrMode: ABY, cycles: 5, fixedCycles: true},
+		{name: "LDY", op: LDY, modeType: "IMM", addrMode: IMM, cycles: 2}, {name: "LDA", op: LDA, modeType: "IDX", addrMode: IDX, cycles: 6}, {name: "LDX", op: LDX, modeType: "IMM", addrMode: IMM, cycles: 2}, {name: "LAX", op: LAX, modeType: "IDX", addrMode: IDX, cycles: 6}, {name: "LDY", op: LDY, modeType: "ZPI", addrMode: ZPI, cycles: 3}, {name: "LDA", op: LDA, modeType: "ZPI", addrMode: ZPI, cycles: 3}, {name: "LDX", op: LDX, modeType: "ZPI", addrMode: ZPI, cycles: 3}, {name: "LAX", op: LAX, modeType: "ZPI", addrMode: ZPI, cycles: 3}, {name: "TAY", op: TAY, modeType: "IMP", addrMode: IMP, cycles: 2}, {name: "LDA", op: LDA, modeType: "IMM", addrMode: IMM, cycles: 2}, {name: "TAX", op: TAX, modeType: "IMP", addrMode: IMP, cycles: 2}, {name: "LXA", op: LXA, modeType: "IMM", addrMode: IMM, cycles: 2}, {name: "LDY", op: LDY, modeType: "ABS", addrMode: ABS, cycles: 4}, {name: "LDA", op: LDA, modeType: "ABS", addrMode: ABS, cycles: 4}, {name: "LDX", op: LDX, modeType: "ABS", addrMode: ABS, cycles: 4}, {name: "LAX", op: LAX, modeType: "ABS", addrMode: ABS, cycles: 4},
+		{name: "BCS", op: BCS, modeType: "REL", addrMode: REL, cycles: 2}, {name: "LDA", op: LDA, modeType: "IDY", addrMode: IDY, cycles: 5}, {name: "KIL", op: KIL, modeType: "IMP", addrMode: IMP, cycles: 2}, {name: "LAX", op: LAX, modeType: "IDY", addrMode: IDY, cycles: 5}, {name: "LDY", op: LDY, modeType: "ZPX", addrMode: ZPX, cycles: 4}, {name: "LDA", op: LDA, modeType: "ZPX", addrMode: ZPX, cycles: 4}, {name: "LDX", op: LDX, modeType: "ZPY", addrMode: ZPY, cycles: 4}, {name: "LAX", op: LAX, modeType: "ZPY", addrMode: ZPY, cycles: 4}, {name: "CLV", op: CLV, modeType: "IMP", addrMode: IMP, cycles: 2}, {name: "LDA", op: LDA, modeType: "ABY", addrMode: ABY, cycles: 4}, {name: "TSX", op: TSX, modeType: "IMP", addrMode: IMP, cycles: 2}, {name: "LAS", op: LAS, modeType: "ABY", addrMode: ABY, cycles: 4}, {name: "LDY", op: LDY, modeType: "ABX", addrMode: ABX, cycles: 4}, {name: "LDA", op: LDA, modeType: "ABX", addrMode: ABX, cycles: 4}, {name: "LDX", op: LDX, modeType: "ABY", addrMode: ABY, cycles: 4}, {name: "LAX", op: LAX, modeType: "ABY", addrMode: ABY, cycles: 4},
+		{name: "CPY", op: CPY, modeType: "IMM", addrMode: IMM, cycles: 2}, {name: "CMP", op: CMP, modeType: "IDX", addrMode: IDX, cycles: 6}, {name: "NOP", op: NOP, modeType: "IMM", addrMode: IMM, cycles: 2}, {name: "DCP", op: DCP, modeType: "IDX", addrMode: IDX, cycles: 8}, {name: "CPY", op: CPY, modeType: "ZPI", addrMode: ZPI, cycles: 3}, {name: "CMP", op: CMP, modeType: "ZPI", addrMode: ZPI, cycles: 3}, {name: "DEC", op: DEC, modeType: "ZPI", addrMode: ZPI, cycles: 5}, {name: "DCP", op: DCP, modeType: "ZPI", addrMode: ZPI, cycles: 5}, {name: "INY", op: INY, modeType: "IMP", addrMode: IMP, cycles: 2}, {name: "CMP", op: CMP, modeType: "IMM", addrMode: IMM, cycles: 2}, {name: "DEX", op: DEX, modeType: "IMP", addrMode: IMP, cycles: 2}, {name: "AXS", op: AXS, modeType: "IMM", addrMode: IMM, cycles: 2}, {name: "CPY", op: CPY, modeType: "ABS", addrMode: ABS, cycles: 4}, {name: "CMP", op: CMP, modeType: "ABS", addrMode: ABS, cycles: 4}, {name: "DEC", op: DEC, modeType: "ABS", addrMode: ABS, cycles: 6}, {name: "DCP", op: DCP, modeType: "ABS", addrMode: ABS, cycles: 6},
+		{name: "BNE", op: BNE, modeType: "REL", addrMode: REL, cycles: 2}, {name: "CMP", op: CMP, modeType: "IDY", addrMode: IDY, cycles: 5}, {name: "KIL", op: KIL, modeType: "IMP", addrMode: IMP, cycles: 2}, {name: "DCP", op: DCP, modeType: "IDY", addrMode: IDY, cycles: 8, fixedCycles: true}, {name: "NOP", op: NOP, modeType: "ZPX", addrMode: ZPX, cycles: 4}, {name: "CMP", op: CMP, modeType: "ZPX", addrMode: ZPX, cycles: 4}, {name: "DEC", op: DEC, modeType: "ZPX", addrMode: ZPX, cycles: 6}, {name: "DCP", op: DCP, modeType: "ZPX", addrMode: ZPX, cycles: 6}, {name: "CLD", op: CLD, modeType: "IMP", addrMode: IMP, cycles: 2}, {name: "CMP", op: CMP, modeType: "ABY", addrMode: ABY, cycles: 4}, {name: "NOP", op: NOP, modeType: "IMP", addrMode: IMP, cycles: 2}, {name: "DCP", op: DCP, modeType: "ABY", addrMode: ABY, cycles: 7, fixedCycles: true}, {name: "NOP", op: NOP, modeType: "ABX", addrMode: ABX, cycles: 4}, {name: "CMP", op: CMP, modeType: "ABX", addrMode: ABX, cycles: 4}, {name: "DEC", op: DEC, modeType: "ABX", addrMode: ABX, cycles: 7, fixedCycles: true}, {name: "DCP", op: DCP, modeType: "ABX", addrMode: ABX, cycles: 7, fixedCycles: true},
+		{name: "CPX", op: CPX, modeType: "IMM", addrMode: IMM, cycles: 2}, {name: "SBC", op: SBC, modeType: "IDX", addrMode: IDX, cycles: 6}, {name: "NOP", op: NOP, modeType: "IMM", addrMode: IMM, cycles: 2}, {name: "ISC", op: ISC, modeType: "IDX", addrMode: IDX, cycles: 8}, {name: "CPX", op: CPX, modeType: "ZPI", addrMode: ZPI, cycles: 3}, {name: "SBC", op: SBC, modeType: "ZPI", addrMode: ZPI, cycles: 3}, {name: "INC", op: INC, modeType: "ZPI", addrMode: ZPI, cycles: 5}, {name: "ISC", op: ISC, modeType: "ZPI", addrMode: ZPI, cycles: 5}, {name: "INX", op: INX, modeType: "IMP", addrMode: IMP, cycles: 2}, {name: "SBC", op: SBC, modeType: "IMM", addrMode: IMM, cycles: 2}, {name: "NOP", op: NOP, modeType: "IMP", addrMode: IMP, cycles: 2}, {name: "SBC", op: SBC, modeType: "IMM", addrMode: IMM, cycles: 2}, {name: "CPX", op: CPX, modeType: "ABS", addrMode: ABS, cycles: 4}, {name: "SBC", op: SBC, modeType: "ABS", addrMode: ABS, cycles: 4}, {name: "INC", op: INC, modeType: "ABS", addrMode: ABS, cycles: 6}, {name: "ISC", op: ISC, modeType: "ABS", addrMode: ABS, cycles: 6},
+		{name: "BEQ", op: BEQ, modeType: "REL", addrMode: REL, cycles: 2}, {name: "SBC", op: SBC, modeType: "IDY", addrMode: IDY, cycles: 5}, {name: "KIL", op: KIL, modeType: "IMP", addrMode: IMP, cycles: 2}, {name: "ISC", op: ISC, modeType: "IDY", addrMode: IDY, cycles: 8, fixedCycles: true}, {name: "NOP", op: NOP, modeType: "ZPX", addrMode: ZPX, cycles: 4}, {name: "SBC", op: SBC, modeType: "ZPX", addrMode: ZPX, cycles: 4}, {name: "INC", op: INC, modeType: "ZPX", addrMode: ZPX, cycles: 6}, {name: "ISC", op: ISC, modeType: "ZPX", addrMode: ZPX, cycles: 6}, {name: "SED", op: SED, modeType: "IMP", addrMode: IMP, cycles: 2}, {name: "SBC", op: SBC, modeType: "ABY", addrMode: ABY, cycles: 4}, {name: "NOP", op: NOP, modeType: "IMP", addrMode: IMP, cycles: 2}, {name: "ISC", op: ISC, modeType: "ABY", addrMode: ABY, cycles: 7, fixedCycles: true}, {name: "NOP", op: NOP, modeType: "ABX", addrMode: ABX, cycles: 4}, {name: "SBC", op: SBC, modeType: "ABX", addrMode: ABX, cycles: 4}, {name: "INC", op: INC, modeType: "ABX", addrMode: ABX, cycles: 7, fixedCycles: true}, {name: "ISC", op: ISC, modeType: "ABX", addrMode: ABX, cycles: 7, fixedCycles: true},
 	}
 	//always set to 1
 	cpu.SetFlag(U, true)
@@ -129,9 +133,9 @@ func (cpu *CPU6502) fetchData() {
 // these four functions can occur at any point in operation, and will go after the current instruction is complete
 // tells the cpu to advance one clock cycle
 func (cpu *CPU6502) Clock() {
-	//TODO breakpoint for passing first frame pass
-	if cpu.pc-1 >= 0xc7af {
-		fmt.Printf("")
+	//a halted cpu doesn't fetch anything else
+	if cpu.halted {
+		return
 	}
 	if cpu.cycles == 0 {
 		cpu.opCode = cpu.Read(cpu.pc, false)
@@ -142,6 +146,9 @@ func (cpu *CPU6502) Clock() {
 		addrCycles := cpu.instructions[cpu.opCode].addrMode(cpu)
 		//get how many extra cycles in the CPU action and do the action
 		cpuCycles := cpu.instructions[cpu.opCode].op(cpu)
+		if cpu.instructions[cpu.opCode].fixedCycles {
+			addrCycles = 0
+		}
 		//add the given amount of cycles with any extra cycles
 		cpu.cycles = cpu.instructions[cpu.opCode].cycles + cpuCycles + addrCycles
 	}
@@ -169,6 +176,7 @@ func (cpu *CPU6502) Reset() {
 	cpu.addrAbs = 0
 	cpu.addrRel = 0
 	cpu.fetchedData = 0
+	cpu.halted = false
 
 	//time it takes to reset
 	cpu.cycles = 8
@@ -176,8 +184,8 @@ func (cpu *CPU6502) Reset() {
 
 // interrupt request, can be ignored depending on the interrupt flag of the status register
 func (cpu *CPU6502) IRQ() {
-	//checks if interrupts are disabled, if so escapes
-	if !cpu.GetFlag(I) {
+	//checks if interrupts are disabled, if so escapes, a halted cpu can't take interrupts either
+	if cpu.GetFlag(I) || cpu.halted {
 		return
 	}
 
@@ -205,8 +213,12 @@ func (cpu *CPU6502) IRQ() {
 	cpu.cycles = 7
 }
 
-// non maskable interrupt request, unable to be ignored
+// non maskable interrupt request, unable to be ignored, except by a halted cpu
 func (cpu *CPU6502) NMI() {
+	if cpu.halted {
+		return
+	}
+
 	//store the program counter on the stack, both bytes, little endian
 	cpu.Write(0x0100+uint16(cpu.sptr), uint8((cpu.pc&0xff00)>>8))
 	cpu.sptr--
@@ -231,6 +243,11 @@ func (cpu *CPU6502) NMI() {
 	cpu.cycles = 8
 }
 
+// if a KIL opcode has stopped the cpu, a reset is the only way out
+func (cpu CPU6502) Halted() bool {
+	return cpu.halted
+}
+
 //addressing mode
 
 // implied addressing, address is implicit in the opcode itself so nothing is needed
@@ -417,33 +434,34 @@ func IND(cpu *CPU6502) uint8 {
 
 //opcodes
 
-// nex, non-existent, placeholder put in to represent unspecified opcodes, more detail can later be put in to give the unspecified opcodes that get used their functionality, for now does nothing
-func NEX(cpu *CPU6502) uint8 {
-	return 0
-}
-
 // adc, add with carry, from specified memory to accumulator
 func ADC(cpu *CPU6502) uint8 {
 	cpu.fetchData()
+	cpu.addWithCarry(cpu.fetchedData)
+
+	return 0
+}
 
+// adds a value and the carry to the accumulator, setting the carry, zero, overflow and negative flags, shared by the instructions that add or subtract
+func (cpu *CPU6502) addWithCarry(value uint8) {
 	carryFlag := 0
 
 	if cpu.GetFlag(C) {
 		carryFlag = 1
 	}
 
-	res := uint16(cpu.fetchedData) + uint16(cpu.a) + uint16(carryFlag)
+	res := uint16(value) + uint16(cpu.a) + uint16(carryFlag)
 
 	//carry flag
 	cpu.SetFlag(C, res&0xff00 > 0)
-	//zero flag
-	cpu.SetFlag(Z, res == 0)
+	//zero flag, only the 8 bits that go in the accumulator count
+	cpu.SetFlag(Z, res&0x00ff == 0)
 
 	//overflow flag
 	//checks if both values are negative and it wrapped to positive
-	if (0x0080&cpu.a&cpu.fetchedData == 0x0080) && 0x0080&res == 0 {
+	if (0x0080&cpu.a&value == 0x0080) && 0x0080&res == 0 {
 		cpu.SetFlag(V, true)
-	} else if ((^(cpu.a | cpu.fetchedData) & 0x0080) == 0x0080) && 0x0080&res == 0x0080 {
+	} else if ((^(cpu.a | value) & 0x0080) == 0x0080) && 0x0080&res == 0x0080 {
 		//checks if both values are positive and it wrapped to negative
 		cpu.SetFlag(V, true)
 	} else {
@@ -455,8 +473,6 @@ func ADC(cpu *CPU6502) uint8 {
 
 	//set the accumulator register to the new value
 	cpu.a = uint8(res & 0x00ff)
-
-	return 0
 }
 
 // and, operates on memory and accumulator
@@ -497,6 +513,8 @@ func ASL(cpu *CPU6502) uint8 {
 		//negative flag
 		cpu.SetFlag(N, uint8(mem&0xff)&0x80 == 0x80)
 
+		//read-modify-write instructions write the unchanged value back before the new one, which some mappers can see
+		cpu.Write(cpu.addrAbs, cpu.fetchedData)
 		cpu.Write(cpu.addrAbs, mem)
 	}
 
@@ -762,6 +780,8 @@ func DEC(cpu *CPU6502) uint8 {
 	//negative flag
 	cpu.SetFlag(N, dec&0x80 == 0x80)
 
+	//read-modify-write instructions write the unchanged value back before the new one, which some mappers can see
+	cpu.Write(cpu.addrAbs, cpu.fetchedData)
 	cpu.Write(cpu.addrAbs, dec)
 	return 0
 }
@@ -819,6 +839,8 @@ func INC(cpu *CPU6502) uint8 {
 	//negative flag
 	cpu.SetFlag(N, dec&0x80 == 0x80)
 
+	//read-modify-write instructions write the unchanged value back before the new one, which some mappers can see
+	cpu.Write(cpu.addrAbs, cpu.fetchedData)
 	cpu.Write(cpu.addrAbs, dec)
 	return 0
 }
@@ -939,14 +961,18 @@ func LSR(cpu *CPU6502) uint8 {
 		//negative flag
 		cpu.SetFlag(N, uint8(mem&0xff)&0x80 == 0x80)
 
+		//read-modify-write instructions write the unchanged value back before the new one, which some mappers can see
+		cpu.Write(cpu.addrAbs, cpu.fetchedData)
 		cpu.Write(cpu.addrAbs, mem)
 	}
 
 	return 0
 }
 
-// nop, no operation, simply passes and lets the clock function increment the program counter, unspecified opcodes can cause a nop to have slightly different behavior, but currently unimplemented
+// nop, no operation, simply passes and lets the clock function increment the program counter
+// the unofficial nops with an operand still read it, which can be seen by the memory at that address
 func NOP(cpu *CPU6502) uint8 {
+	cpu.fetchData()
 	return 0
 }
 
@@ -1048,6 +1074,8 @@ func ROL(cpu *CPU6502) uint8 {
 		//negative flag
 		cpu.SetFlag(N, uint8(mem&0xff)&0x80 == 0x80)
 
+		//read-modify-write instructions write the unchanged value back before the new one, which some mappers can see
+		cpu.Write(cpu.addrAbs, cpu.fetchedData)
 		cpu.Write(cpu.addrAbs, mem)
 	}
 
@@ -1092,6 +1120,8 @@ func ROR(cpu *CPU6502) uint8 {
 		//negative flag
 		cpu.SetFlag(N, uint8(mem&0xff)&0x80 == 0x80)
 
+		//read-modify-write instructions write the unchanged value back before the new one, which some mappers can see
+		cpu.Write(cpu.addrAbs, cpu.fetchedData)
 		cpu.Write(cpu.addrAbs, mem)
 	}
 
@@ -1131,38 +1161,10 @@ func RTS(cpu *CPU6502) uint8 {
 }
 
 // sbc, subtract with carry, from specified memory to accumulator
+// subtracting is adding the inverted value, the carry being set means there's no borrow
 func SBC(cpu *CPU6502) uint8 {
 	cpu.fetchData()
-
-	carryFlag := 0
-
-	if cpu.GetFlag(C) {
-		carryFlag = 1
-	}
-
-	//subtracts the supplied memory byte from the accumulator
-	res := uint16(cpu.a) - uint16(cpu.fetchedData) - ^uint16(carryFlag)
-
-	//carry flag
-	cpu.SetFlag(C, res&0xff00 > 0)
-	//zero flag
-	cpu.SetFlag(Z, res == 0)
-
-	//overflow flag
-	//checks if both values are negative and it wrapped to positive
-	if (0x0080&cpu.a&cpu.fetchedData == 0x0080) && 0x0080&res == 0 {
-		cpu.SetFlag(V, true)
-	}
-	//checks if both values are positive and it wrapped to negative
-	if ((^(cpu.a | cpu.fetchedData) & 0x0080) == 0x0080) && 0x0080&res == 0x0080 {
-		cpu.SetFlag(V, true)
-	}
-
-	//negative flag
-	cpu.SetFlag(N, 0x0080&res == 0x0080)
-
-	//set the accumulator register to the new value
-	cpu.a = uint8(res & 0x00ff)
+	cpu.addWithCarry(^cpu.fetchedData)
 
 	return 0
 }
@@ -1278,3 +1280,260 @@ func TYA(cpu *CPU6502) uint8 {
 
 	return 0
 }
+
+//unofficial opcodes
+//the opcodes the 6502 doesn't document still do things, usually 2 official instructions at once since they share the decoding logic
+//a few games and many test roms use them
+
+// alr, and then logical shift right, ands the accumulator with the operand and shifts it right
+func ALR(cpu *CPU6502) uint8 {
+	cpu.a &= cpu.fetchedData
+
+	//carry flag
+	cpu.SetFlag(C, cpu.a&1 == 1)
+	cpu.a >>= 1
+
+	//zero flag
+	cpu.SetFlag(Z, cpu.a == 0)
+	//negative flag
+	cpu.SetFlag(N, cpu.a&0x80 == 0x80)
+
+	return 0
+}
+
+// anc, and then copy the negative flag to the carry, ands the accumulator with the operand
+func ANC(cpu *CPU6502) uint8 {
+	AND(cpu)
+
+	//carry flag
+	cpu.SetFlag(C, cpu.GetFlag(N))
+
+	return 0
+}
+
+// arr, and then rotate right, ands the accumulator with the operand and rotates it right, the carry and overflow come from bits 5 and 6 of the result
+func ARR(cpu *CPU6502) uint8 {
+	//holds the value of the old carry
+	car := uint8(0)
+	if cpu.GetFlag(C) {
+		car = 0x0080
+	}
+
+	cpu.a = (cpu.a&cpu.fetchedData)>>1 | car
+
+	//zero flag
+	cpu.SetFlag(Z, cpu.a == 0)
+	//negative flag
+	cpu.SetFlag(N, cpu.a&0x80 == 0x80)
+	//carry flag
+	cpu.SetFlag(C, cpu.a&0x40 == 0x40)
+	//overflow flag
+	cpu.SetFlag(V, (cpu.a>>6^cpu.a>>5)&1 == 1)
+
+	return 0
+}
+
+// axs, and x register then subtract, sets the x register to the accumulator anded with the x register minus the operand, without the carry, setting the flags like a compare
+func AXS(cpu *CPU6502) uint8 {
+	and := cpu.a & cpu.x
+
+	//carry flag
+	cpu.SetFlag(C, and >= cpu.fetchedData)
+	cpu.x = and - cpu.fetchedData
+
+	//zero flag
+	cpu.SetFlag(Z, cpu.x == 0)
+	//negative flag
+	cpu.SetFlag(N, cpu.x&0x80 == 0x80)
+
+	return 0
+}
+
+// dcp, decrement then compare, decrements the memory value and compares it with the accumulator
+func DCP(cpu *CPU6502) uint8 {
+	DEC(cpu)
+	dec := cpu.fetchedData - 1
+
+	//carry flag, set if a >= m
+	cpu.SetFlag(C, cpu.a >= dec)
+	//zero, set if a - m == 0, 8 bit
+	cpu.SetFlag(Z, cpu.a-dec == 0)
+	//negative flag, set if a - m < 0
+	cpu.SetFlag(N, (cpu.a-dec)&0x80 == 0x80)
+
+	return 0
+}
+
+// isc, increment then subtract with carry, increments the memory value and subtracts it from the accumulator
+func ISC(cpu *CPU6502) uint8 {
+	INC(cpu)
+	cpu.addWithCarry(^(cpu.fetchedData + 1))
+
+	return 0
+}
+
+// kil, stops the cpu, it stays stuck until it's reset and doesn't take interrupts
+func KIL(cpu *CPU6502) uint8 {
+	//the program counter stays on the kil opcode
+	cpu.pc--
+	cpu.halted = true
+
+	return 0
+}
+
+// las, load accumulator, x register and stack pointer, all 3 are set to the memory value anded with the stack pointer
+func LAS(cpu *CPU6502) uint8 {
+	cpu.fetchData()
+
+	cpu.sptr &= cpu.fetchedData
+	cpu.a = cpu.sptr
+	cpu.x = cpu.sptr
+
+	//zero
+	cpu.SetFlag(Z, cpu.a == 0)
+	//negative flag
+	cpu.SetFlag(N, cpu.a&0x80 == 0x80)
+
+	return 0
+}
+
+// lax, load accumulator and x register, loads the byte of memory into both
+func LAX(cpu *CPU6502) uint8 {
+	LDA(cpu)
+	cpu.x = cpu.a
+
+	return 0
+}
+
+// lxa, immediate lax, the result depends on the chip and what's on the bus, the NES's cpu acts like the accumulator is ored with 0xff first, so both are loaded with the operand
+func LXA(cpu *CPU6502) uint8 {
+	cpu.a = cpu.fetchedData
+	cpu.x = cpu.a
+
+	//zero
+	cpu.SetFlag(Z, cpu.a == 0)
+	//negative flag
+	cpu.SetFlag(N, cpu.a&0x80 == 0x80)
+
+	return 0
+}
+
+// rla, rotate left then and, rotates the memory value left and ands it with the accumulator
+func RLA(cpu *CPU6502) uint8 {
+	//holds the value of the old carry
+	car := uint8(0)
+	if cpu.GetFlag(C) {
+		car = 1
+	}
+
+	ROL(cpu)
+	cpu.a &= cpu.fetchedData<<1 | car
+
+	//zero
+	cpu.SetFlag(Z, cpu.a == 0)
+	//negative flag
+	cpu.SetFlag(N, cpu.a&0x80 == 0x80)
+
+	return 0
+}
+
+// rra, rotate right then add with carry, rotates the memory value right and adds it to the accumulator, the carry coming from the rotate
+func RRA(cpu *CPU6502) uint8 {
+	//holds the value of the old carry
+	car := uint8(0)
+	if cpu.GetFlag(C) {
+		car = 0x0080
+	}
+
+	ROR(cpu)
+	cpu.addWithCarry(cpu.fetchedData>>1 | car)
+
+	return 0
+}
+
+// sax, store accumulator and x register, stores the accumulator anded with the x register to memory, without changing any flags
+func SAX(cpu *CPU6502) uint8 {
+	cpu.Write(cpu.addrAbs, cpu.a&cpu.x)
+
+	return 0
+}
+
+// sha, store accumulator and x register anded with the high byte, stores the accumulator anded with the x register and the high byte of the address plus 1
+func SHA(cpu *CPU6502) uint8 {
+	cpu.storeHigh(cpu.a&cpu.x, cpu.y)
+
+	return 0
+}
+
+// shx, store x register anded with the high byte
+func SHX(cpu *CPU6502) uint8 {
+	cpu.storeHigh(cpu.x, cpu.y)
+
+	return 0
+}
+
+// shy, store y register anded with the high byte
+func SHY(cpu *CPU6502) uint8 {
+	cpu.storeHigh(cpu.y, cpu.x)
+
+	return 0
+}
+
+// slo, shift left then or, shifts the memory value left and ors it with the accumulator
+func SLO(cpu *CPU6502) uint8 {
+	ASL(cpu)
+	cpu.a |= cpu.fetchedData << 1
+
+	//zero
+	cpu.SetFlag(Z, cpu.a == 0)
+	//negative flag
+	cpu.SetFlag(N, cpu.a&0x80 == 0x80)
+
+	return 0
+}
+
+// sre, shift right then exclusive or, shifts the memory value right and xors it with the accumulator
+func SRE(cpu *CPU6502) uint8 {
+	LSR(cpu)
+	cpu.a ^= cpu.fetchedData >> 1
+
+	//zero
+	cpu.SetFlag(Z, cpu.a == 0)
+	//negative flag
+	cpu.SetFlag(N, cpu.a&0x80 == 0x80)
+
+	return 0
+}
+
+// tas, transfer accumulator and x register to the stack pointer, then stores the stack pointer anded with the high byte like shx
+func TAS(cpu *CPU6502) uint8 {
+	cpu.sptr = cpu.a & cpu.x
+	cpu.storeHigh(cpu.sptr, cpu.y)
+
+	return 0
+}
+
+// xaa, x register and accumulator, the result depends on the chip and what's on the bus, usually it acts like the accumulator is ored with 0xee first
+func XAA(cpu *CPU6502) uint8 {
+	cpu.a = (cpu.a | 0xee) & cpu.x & cpu.fetchedData
+
+	//zero
+	cpu.SetFlag(Z, cpu.a == 0)
+	//negative flag
+	cpu.SetFlag(N, cpu.a&0x80 == 0x80)
+
+	return 0
+}
+
+// the sh stores write the value anded with the high byte of the address before indexing plus 1
+// if adding the index crossed a page the high byte of the address is replaced with the value that's written
+func (cpu *CPU6502) storeHigh(value uint8, index uint8) {
+	base := cpu.addrAbs - uint16(index)
+	value &= uint8(base>>8) + 1
+
+	addr := cpu.addrAbs
+	if base&0xff00 != addr&0xff00 {
+		addr = uint16(value)<<8 | addr&0x00ff
+	}
+	cpu.Write(addr, value)
+}
@@ -0,0 +1,198 @@
+package nes
+
+import (
+	"bytes"
+	"testing"
+)
+
+// makes a console with a NROM cartridge running the program from 0x8000, the rest of the ROM is filled with NOPs
+func createTestBus(t *testing.T, program []byte) *Bus {
+	t.Helper()
+
+	prg := bytes.Repeat([]byte{0xea}, 0x8000)
+	copy(prg, program)
+	//the reset vector
+	prg[0x7ffc] = 0x00
+	prg[0x7ffd] = 0x80
+
+	cart, err := LoadCartridgeBytes(append([]byte("NES\x1a\x02\x01\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00"), append(prg, make([]byte, 0x2000)...)...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	bus := CreateBus()
+	bus.InsertCartridge(cart)
+	bus.CPU.Reset()
+	//skip the time the reset takes
+	bus.CPU.cycles = 0
+
+	return bus
+}
+
+// runs the CPU until the instruction it starts is finished, and gives how many cycles it took
+func stepInstruction(cpu *CPU6502) uint8 {
+	cpu.Clock()
+	cycles := cpu.cycles + 1
+	for cpu.cycles > 0 && !cpu.halted {
+		cpu.Clock()
+	}
+
+	return cycles
+}
+
+func TestUnofficialOpcodes(t *testing.T) {
+	tests := []struct {
+		name string
+		//instructions that set up the registers and memory before the one being tested
+		setup []byte
+		op    []byte
+		//the registers, the byte at 0x0010 and the flags from C, Z, V and N after the instruction
+		a, x   uint8
+		mem    uint8
+		flags  uint8
+		cycles uint8
+	}{
+		{
+			name:  "LAX zero page",
+			setup: []byte{0xa9, 0x80, 0x85, 0x10, 0xa9, 0x00, 0xa2, 0x00}, //LDA #$80, STA $10, LDA #$00, LDX #$00
+			op:    []byte{0xa7, 0x10},
+			a:     0x80, x: 0x80, mem: 0x80, flags: N, cycles: 3,
+		},
+		{
+			name:  "LAX absolute,Y crossing a page",
+			setup: []byte{0xa9, 0x10, 0x85, 0x10, 0x8d, 0x00, 0x02, 0xa0, 0x01, 0xa9, 0x00}, //LDA #$10, STA $10, STA $0200, LDY #$01, LDA #$00
+			op:    []byte{0xbf, 0xff, 0x01},
+			a:     0x10, x: 0x10, mem: 0x10, flags: 0, cycles: 5,
+		},
+		{
+			name:  "SAX zero page",
+			setup: []byte{0x38, 0xa9, 0xf0, 0xa2, 0x3c}, //SEC, LDA #$f0, LDX #$3c
+			op:    []byte{0x87, 0x10},
+			a:     0xf0, x: 0x3c, mem: 0x30, flags: C, cycles: 3,
+		},
+		{
+			name:  "DCP zero page",
+			setup: []byte{0xa9, 0x05, 0x85, 0x10}, //LDA #$05, STA $10
+			op:    []byte{0xc7, 0x10},
+			a:     0x05, mem: 0x04, flags: C, cycles: 5,
+		},
+		{
+			name:  "DCP equal",
+			setup: []byte{0xa9, 0x06, 0x85, 0x10, 0xa9, 0x05}, //LDA #$06, STA $10, LDA #$05
+			op:    []byte{0xc7, 0x10},
+			a:     0x05, mem: 0x05, flags: C | Z, cycles: 5,
+		},
+		{
+			name:  "DCP below",
+			setup: []byte{0x85, 0x10}, //STA $10, A is 0 after reset
+			op:    []byte{0xc7, 0x10},
+			a:     0x00, mem: 0xff, flags: 0, cycles: 5,
+		},
+		{
+			name:  "ISC zero page",
+			setup: []byte{0xa9, 0x05, 0x85, 0x10, 0x38, 0xa9, 0x10}, //LDA #$05, STA $10, SEC, LDA #$10
+			op:    []byte{0xe7, 0x10},
+			a:     0x0a, mem: 0x06, flags: C, cycles: 5,
+		},
+		{
+			name:  "ISC borrow",
+			setup: []byte{0x85, 0x10, 0x38}, //STA $10, SEC
+			op:    []byte{0xe7, 0x10},
+			a:     0xff, mem: 0x01, flags: N, cycles: 5,
+		},
+		{
+			name:  "ISC overflow",
+			setup: []byte{0xa9, 0x00, 0x85, 0x10, 0x38, 0xa9, 0x80}, //LDA #$00, STA $10, SEC, LDA #$80
+			op:    []byte{0xe7, 0x10},
+			a:     0x7f, mem: 0x01, flags: C | V, cycles: 5,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			bus := createTestBus(t, append(append([]byte{}, test.setup...), test.op...))
+			start := 0x8000 + uint16(len(test.setup))
+			for bus.CPU.pc != start {
+				stepInstruction(&bus.CPU)
+			}
+
+			cycles := stepInstruction(&bus.CPU)
+			if cycles != test.cycles {
+				t.Errorf("took %d cycles, want %d", cycles, test.cycles)
+			}
+			if bus.CPU.a != test.a || bus.CPU.x != test.x {
+				t.Errorf("A 0x%02x X 0x%02x, want A 0x%02x X 0x%02x", bus.CPU.a, bus.CPU.x, test.a, test.x)
+			}
+			if bus.CPURAM[0x10] != test.mem {
+				t.Errorf("memory 0x%02x, want 0x%02x", bus.CPURAM[0x10], test.mem)
+			}
+			if flags := bus.CPU.status & (C | Z | V | N); flags != test.flags {
+				t.Errorf("flags 0x%02x, want 0x%02x", flags, test.flags)
+			}
+		})
+	}
+}
+
+func TestKILHalts(t *testing.T) {
+	//LDA #$07, KIL, LDA #$09
+	bus := createTestBus(t, []byte{0xa9, 0x07, 0x02, 0xa9, 0x09})
+	for i := 0; i < 3; i++ {
+		stepInstruction(&bus.CPU)
+	}
+
+	if !bus.CPU.Halted() {
+		t.Fatal("the CPU didn't halt")
+	}
+	//clocking or interrupting a halted CPU doesn't get it going again, only a reset does
+	for i := 0; i < 10; i++ {
+		bus.CPU.Clock()
+	}
+	bus.CPU.NMI()
+	bus.CPU.IRQ()
+	//the PC stays on the KIL, like the real CPU which keeps fetching it
+	if bus.CPU.a != 0x07 || bus.CPU.pc != 0x8002 {
+		t.Errorf("A 0x%02x PC 0x%04x after halting, want A 0x07 PC 0x8002", bus.CPU.a, bus.CPU.pc)
+	}
+
+	bus.CPU.Reset()
+	if bus.CPU.Halted() {
+		t.Error("the CPU is still halted after a reset")
+	}
+}
+
+// only instructions that just read take an extra cycle when the indexed address crosses a page
+// writes and read-modify-writes always take the extra cycle, so their count is fixed, official ones included
+func TestPageCrossCycles(t *testing.T) {
+	tests := []struct {
+		name   string
+		op     []byte
+		cycles uint8
+	}{
+		{"LDA absolute,X", []byte{0xbd, 0x00, 0x02}, 4},
+		{"LDA absolute,X crossing", []byte{0xbd, 0x01, 0x02}, 5},
+		{"LDA (indirect),Y crossing", []byte{0xb1, 0x20}, 6},
+		{"NOP absolute,X crossing", []byte{0x1c, 0x01, 0x02}, 5},
+		{"STA absolute,X", []byte{0x9d, 0x00, 0x02}, 5},
+		{"STA absolute,X crossing", []byte{0x9d, 0x01, 0x02}, 5},
+		{"STA (indirect),Y crossing", []byte{0x91, 0x20}, 6},
+		{"INC absolute,X", []byte{0xfe, 0x00, 0x02}, 7},
+		{"INC absolute,X crossing", []byte{0xfe, 0x01, 0x02}, 7},
+		{"SLO absolute,X crossing", []byte{0x1f, 0x01, 0x02}, 7},
+		{"DCP absolute,Y crossing", []byte{0xdb, 0x01, 0x02}, 7},
+		{"ISC (indirect),Y crossing", []byte{0xf3, 0x20}, 8},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			//LDX #$ff, LDY #$ff, and the pointer at 0x20 points to 0x0201 so adding Y crosses a page
+			setup := []byte{0xa2, 0xff, 0xa0, 0xff, 0xa9, 0x01, 0x85, 0x20, 0xa9, 0x02, 0x85, 0x21}
+			bus := createTestBus(t, append(setup, test.op...))
+			for bus.CPU.pc != 0x8000+uint16(len(setup)) {
+				stepInstruction(&bus.CPU)
+			}
+
+			if cycles := stepInstruction(&bus.CPU); cycles != test.cycles {
+				t.Errorf("took %d cycles, want %d", cycles, test.cycles)
+			}
+		})
+	}
+}
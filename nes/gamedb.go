@@ -0,0 +1,176 @@
+package nes
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/xml"
+	"hash/crc32"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// the corrected header information for one game in the database
+type GameInfo struct {
+	//name of the game, taken from the comment before each game in the database
+	Title string
+
+	MapperID    uint16
+	SubmapperID uint8
+	Mirror      Mirror
+	Battery     bool
+
+	PRGRAMSize   int
+	PRGNVRAMSize int
+	CHRRAMSize   int
+	CHRNVRAMSize int
+
+	Timing      Timing
+	ConsoleType ConsoleType
+}
+
+// database of known games, used to fix ROMs with bad headers
+// games are looked up by the hash of their PRG and CHR ROM together, so the header itself doesn't matter
+type GameDB struct {
+	bySHA1  map[[20]byte]*GameInfo
+	byCRC32 map[uint32]*GameInfo
+}
+
+// layout of a game in the NES 2.0 header database XML
+type xmlGame struct {
+	ROM struct {
+		CRC32 string `xml:"crc32,attr"`
+		SHA1  string `xml:"sha1,attr"`
+	} `xml:"rom"`
+	PRGRAM   xmlSize `xml:"prgram"`
+	PRGNVRAM xmlSize `xml:"prgnvram"`
+	CHRRAM   xmlSize `xml:"chrram"`
+	CHRNVRAM xmlSize `xml:"chrnvram"`
+	PCB      struct {
+		Mapper    uint16 `xml:"mapper,attr"`
+		Submapper uint8  `xml:"submapper,attr"`
+		Mirroring string `xml:"mirroring,attr"`
+		Battery   uint8  `xml:"battery,attr"`
+	} `xml:"pcb"`
+	Console struct {
+		Type   uint8 `xml:"type,attr"`
+		Region uint8 `xml:"region,attr"`
+	} `xml:"console"`
+}
+
+type xmlSize struct {
+	Size int `xml:"size,attr"`
+}
+
+// reads a game database file
+func LoadGameDBFile(filename string) (*GameDB, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return LoadGameDB(file)
+}
+
+// reads a game database in the NES 2.0 header database XML format, where each game element has the title in a comment before it
+func LoadGameDB(data io.Reader) (*GameDB, error) {
+	db := GameDB{
+		bySHA1:  map[[20]byte]*GameInfo{},
+		byCRC32: map[uint32]*GameInfo{},
+	}
+
+	decoder := xml.NewDecoder(data)
+	//the last comment seen, which is the title of the next game
+	title := ""
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch token := token.(type) {
+		case xml.Comment:
+			title = strings.TrimSpace(string(token))
+		case xml.StartElement:
+			if token.Name.Local != "game" {
+				continue
+			}
+
+			game := xmlGame{}
+			if err := decoder.DecodeElement(&game, &token); err != nil {
+				return nil, err
+			}
+			db.add(title, &game)
+			title = ""
+		}
+	}
+
+	return &db, nil
+}
+
+// turns a game from the XML into a GameInfo and indexes it by its hashes
+func (db *GameDB) add(title string, game *xmlGame) {
+	//titles are usually the file name, which doesn't need the extension
+	title = strings.TrimSuffix(title, ".nes")
+	//some titles have the path of the file too
+	if slash := strings.LastIndexAny(title, "\\/"); slash >= 0 {
+		title = title[slash+1:]
+	}
+
+	info := GameInfo{
+		Title:        title,
+		MapperID:     game.PCB.Mapper,
+		SubmapperID:  game.PCB.Submapper,
+		Battery:      game.PCB.Battery != 0,
+		PRGRAMSize:   game.PRGRAM.Size,
+		PRGNVRAMSize: game.PRGNVRAM.Size,
+		CHRRAMSize:   game.CHRRAM.Size,
+		CHRNVRAMSize: game.CHRNVRAM.Size,
+		Timing:       Timing(game.Console.Region & 0x03),
+		ConsoleType:  ConsoleType(game.Console.Type),
+	}
+
+	switch game.PCB.Mirroring {
+	case "V":
+		info.Mirror = VERTICAL
+	case "4":
+		info.Mirror = FOURSCREEN
+	default:
+		info.Mirror = HORIZONTAL
+	}
+
+	if sum, err := hex.DecodeString(game.ROM.SHA1); err == nil && len(sum) == 20 {
+		db.bySHA1[[20]byte(sum)] = &info
+	}
+	if sum, err := strconv.ParseUint(game.ROM.CRC32, 16, 32); err == nil {
+		db.byCRC32[uint32(sum)] = &info
+	}
+}
+
+// how many different hashes are in the database
+func (db *GameDB) Len() int {
+	return max(len(db.bySHA1), len(db.byCRC32))
+}
+
+// finds the game with the given PRG and CHR ROM, SHA-1 is checked first since CRC32s can collide, returns nil if it isn't in the database
+func (db *GameDB) Lookup(prg []byte, chr []byte) *GameInfo {
+	hash := sha1.New()
+	hash.Write(prg)
+	hash.Write(chr)
+	if info, ok := db.bySHA1[[20]byte(hash.Sum(nil))]; ok {
+		return info
+	}
+
+	crc := crc32.Update(crc32.ChecksumIEEE(prg), crc32.IEEETable, chr)
+	if info, ok := db.byCRC32[crc]; ok {
+		return info
+	}
+
+	return nil
+}
@@ -1,9 +1,113 @@
 package nes
 
-//mapper interface, many different types of mappers exist on the NES, so this interface allows the use of mappers to be polymorphic
+// mapper interface, many different types of mappers exist on the NES, so this interface allows the use of mappers to be polymorphic
+// the map functions return the offset into the PRG or CHR memory an address is at, and if the address is handled by the cartridge
+// the data written is passed along with the address, since most mappers are controlled by writing to registers in the ROM address range
+// PRG ROM is never written, so CPUMapWrite only says if the cartridge took the write, still returning the ROM offset the address is at
 type Mapper interface {
 	CPUMapRead(addr uint16) (uint32, bool)
-	CPUMapWrite(addr uint16) (uint32, bool)
+	CPUMapWrite(addr uint16, data uint8) (uint32, bool)
 	PPUMapRead(addr uint16) (uint32, bool)
 	PPUMapWrite(addr uint16) (uint32, bool)
 }
+
+// the interfaces below are optional, a mapper only implements the ones for the hardware its board has
+// the cartridge checks for them once when the mapper is made (see mapperHooks), so they can't change while a game runs
+// mirroring: MirrorMapper, NameTablePageMapper, NameTableMapper
+// memory: PRGRAMMapper, PRGRAMBankMapper, CPUDataMapper, PPUDataMapper, SaveRAMMapper
+// timing: CPUClocker, PPUAddressWatcher, PPUFrameWatcher, ResetMapper
+// output: IRQMapper, AudioMapper
+// writes: BusConflictMapper
+
+// mappers that control the nametable mirroring themselves, instead of it being fixed by the header
+type MirrorMapper interface {
+	Mirror() Mirror
+}
+
+// mappers that control access to the PRG-RAM at CPU 0x6000 - 0x7fff, returning the offset into the PRG-RAM and if it can be accessed
+// without this the whole range is mapped to the PRG-RAM
+type PRGRAMMapper interface {
+	PRGRAMMapRead(addr uint16) (uint32, bool)
+	PRGRAMMapWrite(addr uint16) (uint32, bool)
+}
+
+// mappers that need to keep track of time, called once every CPU cycle
+type CPUClocker interface {
+	CPUClock()
+}
+
+// mappers that watch the addresses the PPU puts on its bus, like the MMC3 counting scanlines from A12
+// called for every PPU read and write that can reach the cartridge, after the access happens
+type PPUAddressWatcher interface {
+	PPUAddress(addr uint16)
+}
+
+// mappers that can interrupt the CPU, returns if the IRQ line is being held
+type IRQMapper interface {
+	IRQ() bool
+}
+
+// mappers with registers that can be read, or other memory the CPU can see that isn't PRG ROM or work RAM, like the MMC5's ExRAM
+// returns the data and if the mapper supplied it, checked before CPUMapRead
+type CPUDataMapper interface {
+	CPUReadData(addr uint16) (uint8, bool)
+}
+
+// mappers with pattern memory the PPU can see that isn't in the CHR memory, returns the data and if the mapper supplied it
+// checked before PPUMapRead and PPUMapWrite, only for the pattern tables at 0x0000 - 0x1fff
+type PPUDataMapper interface {
+	PPUReadData(addr uint16) (uint8, bool)
+	PPUWriteData(addr uint16, data uint8) bool
+}
+
+// mappers that can bank PRG-RAM into the ROM range at 0x8000 - 0xffff, like the MMC5
+// returns the offset into the PRG-RAM and if the address is RAM that can be accessed, otherwise the address is handled as ROM
+type PRGRAMBankMapper interface {
+	PRGRAMBankRead(addr uint16) (uint32, bool)
+	PRGRAMBankWrite(addr uint16) (uint32, bool)
+}
+
+// mappers that pick the memory for each of the 4 logical nametables themselves, instead of using one of the mirroring layouts
+// returns 1 KB of memory for each nametable, ciram is the PPU's own 2 KB of nametable memory, the mapper can also give its own memory
+// the cartridge keeps the pages it's given and only asks again after a CPU write to the cartridge or a reset, so they can only depend on the mapper's registers
+type NameTablePageMapper interface {
+	NameTablePages(ciram *[2][1024]uint8) [4][]uint8
+}
+
+// mappers that can put their own memory in the nametables, like the MMC5's ExRAM and fill mode
+// returns if the mapper handled the access, if not the PPU uses the memory for the nametable from NameTablePageMapper or the mirroring
+// for memory that can't simply be read and written, like ROM or memory that changes what it shows while rendering
+type NameTableMapper interface {
+	NameTableRead(addr uint16) (uint8, bool)
+	NameTableWrite(addr uint16, data uint8) bool
+}
+
+// mappers that follow the PPU through the frame, like the MMC5 which counts scanlines and uses different CHR banks for the background and sprites
+// PPUScanline is called at the start of every scanline up to the first vblank line, and PPUFetchingSprites when the PPU switches between fetching sprite and background tiles
+type PPUFrameWatcher interface {
+	PPUScanline(line int, rendering bool)
+	PPUFetchingSprites(sprites bool)
+}
+
+// mappers with their own sound hardware, returns the current output level between -1 and 1, read once every CPU cycle
+type AudioMapper interface {
+	AudioSample() float32
+}
+
+// mappers with RAM of their own that the battery keeps, like the N163's sound RAM, it's saved after the PRG-RAM
+// returns the mapper's memory itself, so loading a save can write to it
+type SaveRAMMapper interface {
+	SaveRAM() []uint8
+}
+
+// mappers that notice the console being reset, most boards can't since the reset button only goes to the CPU and PPU
+// called after the CPU is reset, the registers and memory of most chips keep their values
+type ResetMapper interface {
+	Reset()
+}
+
+// mappers on boards where the ROM still puts its byte on the data bus when the CPU writes to the ROM range, so the 0 bits win
+// the cartridge ANDs the written data with the ROM byte at the address before the mapper gets it, returns if the board has the conflicts
+type BusConflictMapper interface {
+	BusConflicts() bool
+}
@@ -4,12 +4,14 @@ package nes
 
 type Mapper000 struct {
 	//how many banks of memory for each type of data
-	PRGBanks uint8
-	CHRBanks uint8
+	PRGBanks uint16
+	CHRBanks uint16
+	//if the board has CHR-RAM instead of CHR ROM
+	CHRRAM bool
 }
 
-//accesses the CPU memory
-//in mapper 0, if its a 32k PRG ram it maps CPU 0x8000 - 0xfff to ROM 0x0000 - 0x7fff, if it's 16k it mirrors itself to the other half, so CPU 0x8000 - 0xfff to ROM 0x0000 - 0x3fff
+// accesses the CPU memory
+// in mapper 0, if its a 32k PRG ram it maps CPU 0x8000 - 0xfff to ROM 0x0000 - 0x7fff, if it's 16k it mirrors itself to the other half, so CPU 0x8000 - 0xfff to ROM 0x0000 - 0x3fff
 func (mapper Mapper000) CPUMapRead(addr uint16) (uint32, bool) {
 	if addr >= 0x8000 && addr <= 0xffff {
 		if mapper.PRGBanks > 1 {
@@ -23,7 +25,7 @@ func (mapper Mapper000) CPUMapRead(addr uint16) (uint32, bool) {
 	return 0x0000, false
 }
 
-func (mapper Mapper000) CPUMapWrite(addr uint16) (uint32, bool) {
+func (mapper Mapper000) CPUMapWrite(addr uint16, data uint8) (uint32, bool) {
 	if addr >= 0x8000 && addr <= 0xffff {
 		if mapper.PRGBanks > 1 {
 			addr &= 0x7fff
@@ -36,8 +38,8 @@ func (mapper Mapper000) CPUMapWrite(addr uint16) (uint32, bool) {
 	return 0x0000, false
 }
 
-//accesses the PPU memory
-//in mapper 0 the CHR ROM is only 8K, so it's within the limits of the NES hardware already and doesn't need to be mapped
+// accesses the PPU memory
+// in mapper 0 the CHR ROM is only 8K, so it's within the limits of the NES hardware already and doesn't need to be mapped
 func (mapper Mapper000) PPUMapRead(addr uint16) (uint32, bool) {
 	if addr >= 0x0000 && addr <= 0x1fff {
 		return uint32(addr), true
@@ -46,8 +48,11 @@ func (mapper Mapper000) PPUMapRead(addr uint16) (uint32, bool) {
 	return 0x0000, false
 }
 
-//since it is CHR ROM only here, it doesn't let you write
+// CHR ROM can't be written to, but boards with CHR-RAM let the PPU write tiles into it
 func (mapper Mapper000) PPUMapWrite(addr uint16) (uint32, bool) {
+	if addr >= 0x0000 && addr <= 0x1fff && mapper.CHRRAM {
+		return uint32(addr), true
+	}
 
 	return 0x0000, false
 }
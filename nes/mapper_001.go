@@ -0,0 +1,192 @@
+package nes
+
+//mapper for the Nintendo MMC1, NES-SxROM boards
+
+type Mapper001 struct {
+	//how many banks of memory for each type of data, CHR counts CHR-RAM
+	PRGBanks uint16
+	CHRBanks uint16
+	//if the board has CHR-RAM instead of CHR ROM
+	CHRRAM bool
+
+	//the registers are written one bit at a time through this, the 5th write copies it into the register picked by the address
+	shiftRegister uint8
+	//how many bits have been written into the shift register
+	shiftCount uint8
+
+	//CPPMM, CHR bank mode, PRG bank mode, mirroring
+	control uint8
+	//which 4 KB CHR banks are used, in 8 KB mode the low bit of chrBank0 is ignored and chrBank1 isn't used
+	chrBank0 uint8
+	chrBank1 uint8
+	//RPPPP, PRG-RAM disable, 16 KB PRG bank
+	prgBank uint8
+
+	//CPU cycle count, used to ignore writes on back to back cycles
+	cycle uint64
+	//the cycle the last register write happened on
+	lastWrite uint64
+}
+
+func CreateMapper001(prgBanks uint16, chrBanks uint16, chrRAM bool) *Mapper001 {
+	mapper := Mapper001{
+		PRGBanks: prgBanks,
+		CHRBanks: chrBanks,
+		CHRRAM:   chrRAM,
+	}
+	//starts with the last PRG bank fixed at 0xc000 so the reset vector can be found
+	mapper.control = 0x0c
+	//makes sure the first write isn't treated as back to back with the power on
+	mapper.cycle = 2
+
+	return &mapper
+}
+
+// accesses the CPU memory
+// in mapper 1, the PRG bank mode decides how the 16 KB banks are used, either switching 32 KB at a time, or fixing the first or last bank and switching the other half
+// boards with 512 KB of PRG ROM (SUROM) use bit 4 of the CHR bank register to pick which 256 KB half is used
+func (mapper *Mapper001) CPUMapRead(addr uint16) (uint32, bool) {
+	if addr >= 0x8000 && addr <= 0xffff {
+		//the 256 KB outer bank
+		outer := uint32(0)
+		banks := uint32(mapper.PRGBanks)
+		if mapper.PRGBanks > 16 {
+			outer = uint32(mapper.chrBank0&0x10) << 14
+			banks = 16
+		}
+
+		bank := uint32(mapper.prgBank & 0x0f)
+		switch (mapper.control >> 2) & 0x03 {
+		case 0, 1:
+			//32 KB mode, the low bit of the bank number is ignored
+			bank = (bank &^ 1) | uint32((addr>>14)&1)
+		case 2:
+			//first bank fixed at 0x8000, switch 0xc000
+			if addr < 0xc000 {
+				bank = 0
+			}
+		case 3:
+			//last bank fixed at 0xc000, switch 0x8000
+			if addr >= 0xc000 {
+				bank = banks - 1
+			}
+		}
+
+		return outer | (bank%banks)*0x4000 | uint32(addr&0x3fff), true
+	}
+
+	return 0x0000, false
+}
+
+// writes to 0x8000 - 0xffff go to the shift register instead of the ROM
+func (mapper *Mapper001) CPUMapWrite(addr uint16, data uint8) (uint32, bool) {
+	if addr >= 0x8000 && addr <= 0xffff {
+		//the MMC1 ignores a write on the cycle right after another one, read-modify-write instructions write twice in a row, so only the first counts
+		mapAddr, _ := mapper.CPUMapRead(addr)
+		backToBack := mapper.cycle <= mapper.lastWrite+1
+		mapper.lastWrite = mapper.cycle
+		if backToBack {
+			return mapAddr, true
+		}
+
+		//writing with bit 7 set resets the shift register and goes back to fixing the last PRG bank
+		if data&0x80 == 0x80 {
+			mapper.shiftRegister = 0
+			mapper.shiftCount = 0
+			mapper.control |= 0x0c
+			return mapAddr, true
+		}
+
+		//bits come in lowest first, so they are shifted in from the top
+		mapper.shiftRegister = (mapper.shiftRegister >> 1) | ((data & 1) << 4)
+		mapper.shiftCount++
+
+		if mapper.shiftCount == 5 {
+			//bits 13 and 14 of the address of the 5th write pick the register
+			switch (addr >> 13) & 0x03 {
+			case 0:
+				mapper.control = mapper.shiftRegister
+			case 1:
+				mapper.chrBank0 = mapper.shiftRegister
+			case 2:
+				mapper.chrBank1 = mapper.shiftRegister
+			case 3:
+				mapper.prgBank = mapper.shiftRegister
+			}
+
+			mapper.shiftRegister = 0
+			mapper.shiftCount = 0
+		}
+
+		return mapAddr, true
+	}
+
+	return 0x0000, false
+}
+
+// accesses the PPU memory
+// in mapper 1, the CHR can be switched as one 8 KB bank or two 4 KB banks
+func (mapper *Mapper001) PPUMapRead(addr uint16) (uint32, bool) {
+	if addr >= 0x0000 && addr <= 0x1fff {
+		return mapper.chrAddress(addr), true
+	}
+
+	return 0x0000, false
+}
+
+// boards with CHR-RAM use the same banking for writes
+func (mapper *Mapper001) PPUMapWrite(addr uint16) (uint32, bool) {
+	if addr >= 0x0000 && addr <= 0x1fff && mapper.CHRRAM {
+		return mapper.chrAddress(addr), true
+	}
+
+	return 0x0000, false
+}
+
+// gets the offset into CHR memory for a PPU address using the current banks
+func (mapper *Mapper001) chrAddress(addr uint16) uint32 {
+	//there are 2 4 KB banks in each 8 KB bank
+	banks := uint32(mapper.CHRBanks) * 2
+	if banks == 0 {
+		banks = 2
+	}
+
+	var bank uint32
+	if mapper.control&0x10 == 0 {
+		//8 KB mode, the low bit picks which half of the 8 KB bank
+		bank = uint32(mapper.chrBank0&^1) | uint32(addr>>12)
+	} else if addr < 0x1000 {
+		bank = uint32(mapper.chrBank0)
+	} else {
+		bank = uint32(mapper.chrBank1)
+	}
+
+	return (bank%banks)*0x1000 | uint32(addr&0x0fff)
+}
+
+// the mirroring is set by the low 2 bits of the control register
+func (mapper *Mapper001) Mirror() Mirror {
+	switch mapper.control & 0x03 {
+	case 0:
+		return ONESCREEN_LO
+	case 1:
+		return ONESCREEN_HI
+	case 2:
+		return VERTICAL
+	}
+
+	return HORIZONTAL
+}
+
+// the PRG-RAM can be turned off by bit 4 of the PRG bank register
+func (mapper *Mapper001) PRGRAMMapRead(addr uint16) (uint32, bool) {
+	return uint32(addr - 0x6000), mapper.prgBank&0x10 == 0
+}
+
+func (mapper *Mapper001) PRGRAMMapWrite(addr uint16) (uint32, bool) {
+	return uint32(addr - 0x6000), mapper.prgBank&0x10 == 0
+}
+
+func (mapper *Mapper001) CPUClock() {
+	mapper.cycle++
+}
@@ -0,0 +1,62 @@
+package nes
+
+//mapper for NES-UNROM, NES-UOROM
+
+type Mapper002 struct {
+	//how many banks of memory for each type of data
+	PRGBanks uint16
+	CHRBanks uint16
+	//if the board has CHR-RAM instead of CHR ROM, almost all UxROM boards do
+	CHRRAM bool
+
+	//which 16 KB bank is at 0x8000
+	prgBank uint8
+
+	//if writes to the ROM range are ANDed with the ROM's data
+	busConflicts bool
+}
+
+// accesses the CPU memory
+// in mapper 2, 0x8000 - 0xbfff is a switchable 16 KB bank and 0xc000 - 0xffff is fixed to the last bank
+func (mapper *Mapper002) CPUMapRead(addr uint16) (uint32, bool) {
+	if addr >= 0x8000 && addr <= 0xbfff {
+		return (uint32(mapper.prgBank)%uint32(mapper.PRGBanks))*0x4000 | uint32(addr&0x3fff), true
+	}
+	if addr >= 0xc000 && addr <= 0xffff {
+		return uint32(mapper.PRGBanks-1)*0x4000 | uint32(addr&0x3fff), true
+	}
+
+	return 0x0000, false
+}
+
+// writing anywhere in 0x8000 - 0xffff selects the bank at 0x8000
+func (mapper *Mapper002) CPUMapWrite(addr uint16, data uint8) (uint32, bool) {
+	if addr >= 0x8000 && addr <= 0xffff {
+		mapAddr, _ := mapper.CPUMapRead(addr)
+		mapper.prgBank = data
+		return mapAddr, true
+	}
+
+	return 0x0000, false
+}
+
+// the CHR isn't banked, so it's mapped directly
+func (mapper *Mapper002) PPUMapRead(addr uint16) (uint32, bool) {
+	if addr >= 0x0000 && addr <= 0x1fff {
+		return uint32(addr), true
+	}
+
+	return 0x0000, false
+}
+
+func (mapper *Mapper002) PPUMapWrite(addr uint16) (uint32, bool) {
+	if addr >= 0x0000 && addr <= 0x1fff && mapper.CHRRAM {
+		return uint32(addr), true
+	}
+
+	return 0x0000, false
+}
+
+func (mapper *Mapper002) BusConflicts() bool {
+	return mapper.busConflicts
+}
@@ -0,0 +1,65 @@
+package nes
+
+//mapper for NES-CNROM
+
+type Mapper003 struct {
+	//how many banks of memory for each type of data, CHR counts CHR-RAM
+	PRGBanks uint16
+	CHRBanks uint16
+	//if the board has CHR-RAM instead of CHR ROM
+	CHRRAM bool
+
+	//which 8 KB CHR bank is used
+	chrBank uint8
+
+	//if writes to the ROM range are ANDed with the ROM's data
+	busConflicts bool
+}
+
+// accesses the CPU memory
+// in mapper 3 the PRG ROM is the same as mapper 0, 32 KB, or 16 KB mirrored to both halves
+func (mapper *Mapper003) CPUMapRead(addr uint16) (uint32, bool) {
+	if addr >= 0x8000 && addr <= 0xffff {
+		if mapper.PRGBanks > 1 {
+			addr &= 0x7fff
+		} else {
+			addr &= 0x3fff
+		}
+		return uint32(addr), true
+	}
+
+	return 0x0000, false
+}
+
+// writing anywhere in 0x8000 - 0xffff selects the CHR bank
+func (mapper *Mapper003) CPUMapWrite(addr uint16, data uint8) (uint32, bool) {
+	if addr >= 0x8000 && addr <= 0xffff {
+		mapAddr, _ := mapper.CPUMapRead(addr)
+		mapper.chrBank = data
+		return mapAddr, true
+	}
+
+	return 0x0000, false
+}
+
+// accesses the PPU memory, all 8 KB is switched at once
+func (mapper *Mapper003) PPUMapRead(addr uint16) (uint32, bool) {
+	if addr >= 0x0000 && addr <= 0x1fff {
+		return (uint32(mapper.chrBank)%uint32(mapper.CHRBanks))*0x2000 | uint32(addr), true
+	}
+
+	return 0x0000, false
+}
+
+// CNROM only has CHR ROM, but a few homebrew boards put banked CHR-RAM in its place
+func (mapper *Mapper003) PPUMapWrite(addr uint16) (uint32, bool) {
+	if addr >= 0x0000 && addr <= 0x1fff && mapper.CHRRAM {
+		return mapper.PPUMapRead(addr)
+	}
+
+	return 0x0000, false
+}
+
+func (mapper *Mapper003) BusConflicts() bool {
+	return mapper.busConflicts
+}
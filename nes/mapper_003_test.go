@@ -0,0 +1,29 @@
+package nes
+
+import "testing"
+
+func TestMapper003Banks(t *testing.T) {
+	create := func() Mapper {
+		return &Mapper003{PRGBanks: 1, CHRBanks: 4}
+	}
+
+	runBankTests(t, create, []bankTest{
+		{name: "16 KB prg mirrored", addr: 0xc123, want: 0x00123},
+		{name: "power on chr", ppu: true, addr: 0x1234, want: 0x01234},
+		{name: "chr bank", writes: []registerWrite{{0x8000, 0x03}}, ppu: true, addr: 0x0400, want: 0x06400},
+		{name: "chr bank wraps", writes: []registerWrite{{0xffff, 0x05}}, ppu: true, addr: 0x0000, want: 0x02000},
+	})
+}
+
+func TestMapper003CHRRAM(t *testing.T) {
+	rom := &Mapper003{PRGBanks: 2, CHRBanks: 4}
+	if _, ok := rom.PPUMapWrite(0x0000); ok {
+		t.Error("CHR ROM took a write")
+	}
+
+	ram := &Mapper003{PRGBanks: 2, CHRBanks: 4, CHRRAM: true}
+	ram.CPUMapWrite(0x8000, 0x02)
+	if got, ok := ram.PPUMapWrite(0x0123); !ok || got != 0x04123 {
+		t.Errorf("0x0123 write mapped to 0x%05x (%v), want 0x04123", got, ok)
+	}
+}
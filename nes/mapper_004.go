@@ -0,0 +1,211 @@
+package nes
+
+// mapper for the Nintendo MMC3, NES-TxROM boards
+
+type Mapper004 struct {
+	//how many banks of memory for each type of data, CHR counts CHR-RAM
+	PRGBanks uint16
+	CHRBanks uint16
+	//if the board has CHR-RAM instead of CHR ROM
+	CHRRAM bool
+
+	//CP-- -RRR, CHR A12 inversion, PRG bank mode, which bank register the next data write goes to
+	bankSelect uint8
+	//R0 - R7, R0 and R1 are 2 KB CHR banks, R2 - R5 are 1 KB CHR banks, R6 and R7 are 8 KB PRG banks
+	registers [8]uint8
+
+	mirror Mirror
+	//RW-- ----, PRG-RAM enable, PRG-RAM write protect
+	prgRAMProtect uint8
+
+	//scanline counter, reloaded from the latch when it hits 0
+	irqLatch   uint8
+	irqCounter uint8
+	irqReload  bool
+	irqEnable  bool
+	irqActive  bool
+
+	//the state of PPU A12 the last time the PPU used the bus, and the CPU cycle it went low on
+	a12     bool
+	a12Fell uint64
+	cycle   uint64
+}
+
+func CreateMapper004(prgBanks uint16, chrBanks uint16, chrRAM bool, mirror Mirror) *Mapper004 {
+	mapper := Mapper004{
+		PRGBanks: prgBanks,
+		CHRBanks: chrBanks,
+		CHRRAM:   chrRAM,
+		mirror:   mirror,
+	}
+	//the PRG-RAM is left enabled, some games never turn it on themselves
+	mapper.prgRAMProtect = 0x80
+
+	return &mapper
+}
+
+// accesses the CPU memory
+// in mapper 4 the PRG is 4 8 KB banks, the last is always fixed to the last bank, 0xa000 is R7, and the PRG bank mode swaps which of 0x8000 and 0xc000 is R6 and which is fixed to the second last bank
+func (mapper *Mapper004) CPUMapRead(addr uint16) (uint32, bool) {
+	if addr >= 0x8000 && addr <= 0xffff {
+		banks := uint32(mapper.PRGBanks) * 2
+		secondLast := banks - 2
+
+		var bank uint32
+		switch (addr - 0x8000) / 0x2000 {
+		case 0:
+			if mapper.bankSelect&0x40 == 0 {
+				bank = uint32(mapper.registers[6])
+			} else {
+				bank = secondLast
+			}
+		case 1:
+			bank = uint32(mapper.registers[7])
+		case 2:
+			if mapper.bankSelect&0x40 == 0 {
+				bank = secondLast
+			} else {
+				bank = uint32(mapper.registers[6])
+			}
+		case 3:
+			bank = banks - 1
+		}
+
+		return (bank%banks)*0x2000 | uint32(addr&0x1fff), true
+	}
+
+	return 0x0000, false
+}
+
+// the registers are paired up in 0x2000 byte ranges, even addresses are the first of the pair, odd addresses the second
+func (mapper *Mapper004) CPUMapWrite(addr uint16, data uint8) (uint32, bool) {
+	if addr < 0x8000 {
+		return 0x0000, false
+	}
+
+	mapAddr, _ := mapper.CPUMapRead(addr)
+	even := addr&1 == 0
+
+	switch {
+	case addr <= 0x9fff && even:
+		mapper.bankSelect = data
+	case addr <= 0x9fff:
+		mapper.registers[mapper.bankSelect&0x07] = data
+	case addr <= 0xbfff && even:
+		if data&1 == 0 {
+			mapper.mirror = VERTICAL
+		} else {
+			mapper.mirror = HORIZONTAL
+		}
+	case addr <= 0xbfff:
+		mapper.prgRAMProtect = data
+	case addr <= 0xdfff && even:
+		mapper.irqLatch = data
+	case addr <= 0xdfff:
+		//the counter is reloaded on the next scanline
+		mapper.irqCounter = 0
+		mapper.irqReload = true
+	case even:
+		//turning the IRQ off also acknowledges one that is pending
+		mapper.irqEnable = false
+		mapper.irqActive = false
+	default:
+		mapper.irqEnable = true
+	}
+
+	return mapAddr, true
+}
+
+// accesses the PPU memory
+// in mapper 4 the CHR is 2 2 KB banks and 4 1 KB banks, the A12 inversion bit swaps which pattern table has the 2 KB banks
+func (mapper *Mapper004) PPUMapRead(addr uint16) (uint32, bool) {
+	if addr >= 0x0000 && addr <= 0x1fff {
+		return mapper.chrAddress(addr), true
+	}
+
+	return 0x0000, false
+}
+
+func (mapper *Mapper004) PPUMapWrite(addr uint16) (uint32, bool) {
+	if addr >= 0x0000 && addr <= 0x1fff && mapper.CHRRAM {
+		return mapper.chrAddress(addr), true
+	}
+
+	return 0x0000, false
+}
+
+// gets the offset into CHR memory for a PPU address using the current banks
+func (mapper *Mapper004) chrAddress(addr uint16) uint32 {
+	if mapper.bankSelect&0x80 == 0x80 {
+		addr ^= 0x1000
+	}
+
+	//there are 8 1 KB banks in each 8 KB bank
+	banks := uint32(mapper.CHRBanks) * 8
+	if banks == 0 {
+		banks = 8
+	}
+
+	var bank uint32
+	switch slot := addr / 0x0400; slot {
+	case 0, 1:
+		//the 2 KB banks ignore the low bit of the register
+		bank = uint32(mapper.registers[0]&0xfe) | uint32(slot)
+	case 2, 3:
+		bank = uint32(mapper.registers[1]&0xfe) | uint32(slot-2)
+	default:
+		bank = uint32(mapper.registers[slot-2])
+	}
+
+	return (bank%banks)*0x0400 | uint32(addr&0x03ff)
+}
+
+func (mapper *Mapper004) Mirror() Mirror {
+	return mapper.mirror
+}
+
+// the PRG-RAM has to be enabled to be used, and can be made read only
+func (mapper *Mapper004) PRGRAMMapRead(addr uint16) (uint32, bool) {
+	return uint32(addr - 0x6000), mapper.prgRAMProtect&0x80 == 0x80
+}
+
+func (mapper *Mapper004) PRGRAMMapWrite(addr uint16) (uint32, bool) {
+	return uint32(addr - 0x6000), mapper.prgRAMProtect&0xc0 == 0x80
+}
+
+// watches PPU A12 to count scanlines, A12 goes from low to high once per scanline when the background and sprites use different pattern tables
+// the MMC3 filters A12 so it has to be low for more than 3 CPU cycles before a rise counts, otherwise the background fetches within a line would count too
+func (mapper *Mapper004) PPUAddress(addr uint16) {
+	a12 := addr&0x1000 == 0x1000
+
+	if a12 && !mapper.a12 && mapper.cycle-mapper.a12Fell > 3 {
+		mapper.clockIRQCounter()
+	}
+	if !a12 && mapper.a12 {
+		mapper.a12Fell = mapper.cycle
+	}
+
+	mapper.a12 = a12
+}
+
+// counts down a scanline, firing the IRQ when the counter reaches 0
+func (mapper *Mapper004) clockIRQCounter() {
+	if mapper.irqCounter == 0 || mapper.irqReload {
+		mapper.irqCounter = mapper.irqLatch
+		mapper.irqReload = false
+	} else {
+		mapper.irqCounter--
+	}
+
+	if mapper.irqCounter == 0 && mapper.irqEnable {
+		mapper.irqActive = true
+	}
+}
+
+func (mapper *Mapper004) IRQ() bool {
+	return mapper.irqActive
+}
+
+func (mapper *Mapper004) CPUClock() {
+	mapper.cycle++
+}
@@ -0,0 +1,482 @@
+package nes
+
+// mapper for the Nintendo MMC5, NES-EKROM, NES-ELROM, NES-ETROM, NES-EWROM
+
+type Mapper005 struct {
+	//how many banks of memory for each type of data, CHR counts CHR-RAM
+	PRGBanks uint16
+	CHRBanks uint16
+	//if the board has CHR-RAM instead of CHR ROM
+	CHRRAM bool
+
+	//---- --PP, PRG bank mode, 0 is 1 32 KB bank, 1 is 2 16 KB banks, 2 is a 16 KB bank and 2 8 KB banks, 3 is 4 8 KB banks
+	prgMode uint8
+	//---- --CC, CHR bank mode, 0 is 8 KB banks, 1 is 4 KB, 2 is 2 KB, 3 is 1 KB
+	chrMode uint8
+	//the PRG-RAM can only be written when the first is 2 and the second is 1
+	prgRAMProtect [2]uint8
+	//---- -BBB, the PRG-RAM bank at 0x6000
+	prgRAMBank uint8
+	//RBBB BBBB, the banks for 0x8000, 0xa000, 0xc000, 0xe000, R is set for ROM and clear for RAM, 0xe000 is always ROM
+	prgBanks [4]uint8
+
+	//CHR set A, used for sprites, and set B used for the background when sprites are 8x16
+	chrA [8]uint16
+	chrB [4]uint16
+	//---- --UU, the upper bits added to the CHR banks when they're written
+	chrUpper uint8
+	//which set was written last, which is used for everything when the sprites are 8x8
+	lastSetB bool
+
+	//---- --XX, what the ExRAM is used for, 0 is a nametable, 1 is extended attributes, 2 is CPU RAM, 3 is CPU ROM
+	exRAMMode uint8
+	exRAM     [1024]uint8
+	//DDCC BBAA, which memory each of the 4 nametables use, 0 and 1 are the PPU's own, 2 is the ExRAM, 3 is the fill mode tile
+	nameTables uint8
+	fillTile   uint8
+	//---- --AA, the palette of the fill mode tile
+	fillAttrib uint8
+
+	//ES-T TTTT, split enable, split on the right side, which tile the split starts or ends at
+	splitControl uint8
+	splitScroll  uint8
+	//the 4 KB CHR bank the split uses
+	splitBank uint8
+
+	//the 2 numbers to multiply, and are read back as their 16 bit product
+	multiplicand uint8
+	multiplier   uint8
+
+	//scanline IRQ, fires when the scanline counter reaches the compare value
+	irqCompare uint8
+	irqEnable  bool
+	irqPending bool
+	inFrame    bool
+	scanline   uint8
+
+	//what the PPU is doing, watched to pick the CHR banks and the split
+	sprite16        bool
+	rendering       bool
+	fetchingSprites bool
+	//the tile of the line being fetched, the vertical position of the split, and if the tile is in the split
+	tile     uint8
+	splitY   uint8
+	inSplit  bool
+	fetchY   int
+	exLatch  uint8
+	exActive bool
+
+	audio mmc5Audio
+}
+
+func CreateMapper005(prgBanks uint16, chrBanks uint16, chrRAM bool) *Mapper005 {
+	mapper := Mapper005{
+		PRGBanks: prgBanks,
+		CHRBanks: chrBanks,
+		CHRRAM:   chrRAM,
+	}
+	//the last bank is put at 0xe000 in 8 KB mode at power on, so the reset vector can be found
+	mapper.prgMode = 3
+	mapper.prgBanks[3] = 0xff
+	mapper.chrMode = 3
+	mapper.multiplicand = 0xff
+	mapper.multiplier = 0xff
+
+	return &mapper
+}
+
+// finds the 8 KB bank for a CPU address in 0x8000 - 0xffff, and if that bank is ROM or RAM
+func (mapper *Mapper005) prgBank(addr uint16) (uint32, bool) {
+	slot := (addr - 0x8000) / 0x2000
+
+	var reg uint8
+	switch mapper.prgMode {
+	case 0:
+		//the low 2 bits of the register are replaced with which 8 KB of the 32 KB the address is in
+		return uint32(mapper.prgBanks[3]&0x7c) | uint32(slot), true
+	case 1:
+		if slot < 2 {
+			reg = mapper.prgBanks[1]
+		} else {
+			reg = mapper.prgBanks[3]
+		}
+		return uint32(reg&0x7e) | uint32(slot&1), reg&0x80 == 0x80 || slot >= 2
+	case 2:
+		if slot < 2 {
+			reg = mapper.prgBanks[1]
+			return uint32(reg&0x7e) | uint32(slot&1), reg&0x80 == 0x80
+		}
+	}
+
+	reg = mapper.prgBanks[slot]
+	return uint32(reg & 0x7f), reg&0x80 == 0x80 || slot == 3
+}
+
+// accesses the CPU memory
+// ROM banks are mapped here, and RAM banks are left for PRGRAMBankRead
+func (mapper *Mapper005) CPUMapRead(addr uint16) (uint32, bool) {
+	if addr >= 0x8000 && addr <= 0xffff {
+		bank, rom := mapper.prgBank(addr)
+		if !rom {
+			return 0x0000, false
+		}
+		return (bank%(uint32(mapper.PRGBanks)*2))*0x2000 | uint32(addr&0x1fff), true
+	}
+
+	return 0x0000, false
+}
+
+// the registers are at 0x5000 - 0x5206, the ExRAM at 0x5c00 - 0x5fff, writes to the ROM don't do anything
+// writes to the PPU's registers are watched too, to know the sprite size and if the PPU is rendering, but they still go to the PPU
+func (mapper *Mapper005) CPUMapWrite(addr uint16, data uint8) (uint32, bool) {
+	switch {
+	case addr >= 0x2000 && addr <= 0x3fff:
+		switch addr & 0x0007 {
+		case 0:
+			mapper.sprite16 = data&0x20 == 0x20
+		case 1:
+			if data&0x18 == 0 {
+				mapper.inFrame = false
+				mapper.rendering = false
+			}
+		}
+		return 0x0000, false
+	case addr >= 0x5000 && addr <= 0x5015:
+		mapper.audio.write(addr, data)
+		return 0x0000, true
+	case addr >= 0x5100 && addr <= 0x5206:
+		mapper.writeRegister(addr, data)
+		return 0x0000, true
+	case addr >= 0x5c00 && addr <= 0x5fff:
+		switch mapper.exRAMMode {
+		case 0, 1:
+			//as a nametable the ExRAM can only be written while the PPU is rendering, otherwise 0 is written
+			if !mapper.inFrame {
+				data = 0
+			}
+			mapper.exRAM[addr-0x5c00] = data
+		case 2:
+			mapper.exRAM[addr-0x5c00] = data
+		}
+		return 0x0000, true
+	case addr >= 0x8000 && addr <= 0xffff:
+		return mapper.CPUMapRead(addr)
+	}
+
+	return 0x0000, false
+}
+
+func (mapper *Mapper005) writeRegister(addr uint16, data uint8) {
+	switch {
+	case addr == 0x5100:
+		mapper.prgMode = data & 0x03
+	case addr == 0x5101:
+		mapper.chrMode = data & 0x03
+	case addr == 0x5102:
+		mapper.prgRAMProtect[0] = data & 0x03
+	case addr == 0x5103:
+		mapper.prgRAMProtect[1] = data & 0x03
+	case addr == 0x5104:
+		mapper.exRAMMode = data & 0x03
+	case addr == 0x5105:
+		mapper.nameTables = data
+	case addr == 0x5106:
+		mapper.fillTile = data
+	case addr == 0x5107:
+		mapper.fillAttrib = data & 0x03
+	case addr == 0x5113:
+		mapper.prgRAMBank = data & 0x07
+	case addr >= 0x5114 && addr <= 0x5117:
+		mapper.prgBanks[addr-0x5114] = data
+	case addr >= 0x5120 && addr <= 0x5127:
+		mapper.chrA[addr-0x5120] = uint16(data) | uint16(mapper.chrUpper)<<8
+		mapper.lastSetB = false
+	case addr >= 0x5128 && addr <= 0x512b:
+		mapper.chrB[addr-0x5128] = uint16(data) | uint16(mapper.chrUpper)<<8
+		mapper.lastSetB = true
+	case addr == 0x5130:
+		mapper.chrUpper = data & 0x03
+	case addr == 0x5200:
+		mapper.splitControl = data
+	case addr == 0x5201:
+		mapper.splitScroll = data
+	case addr == 0x5202:
+		mapper.splitBank = data
+	case addr == 0x5203:
+		mapper.irqCompare = data
+	case addr == 0x5204:
+		mapper.irqEnable = data&0x80 == 0x80
+	case addr == 0x5205:
+		mapper.multiplicand = data
+	case addr == 0x5206:
+		mapper.multiplier = data
+	}
+}
+
+// the readable registers, the IRQ status, the multiplier's product, the audio status and the ExRAM when it's used as CPU memory
+func (mapper *Mapper005) CPUReadData(addr uint16) (uint8, bool) {
+	switch {
+	case addr == 0x5010:
+		//the PCM IRQ only happens in read mode, which isn't emulated, so it's never set
+		return mapper.audio.pcmControl & 0x01, true
+	case addr == 0x5015:
+		return mapper.audio.status(), true
+	case addr == 0x5204:
+		//IP-- ----, IRQ pending, in frame, reading acknowledges the IRQ
+		status := uint8(0)
+		if mapper.irqPending {
+			status |= 0x80
+		}
+		if mapper.inFrame {
+			status |= 0x40
+		}
+		mapper.irqPending = false
+		return status, true
+	case addr == 0x5205:
+		return uint8(uint16(mapper.multiplicand) * uint16(mapper.multiplier)), true
+	case addr == 0x5206:
+		return uint8((uint16(mapper.multiplicand) * uint16(mapper.multiplier)) >> 8), true
+	case addr >= 0x5c00 && addr <= 0x5fff && mapper.exRAMMode >= 2:
+		return mapper.exRAM[addr-0x5c00], true
+	}
+
+	return 0x0000, false
+}
+
+// the PRG-RAM at 0x6000 - 0x7fff is banked, and can only be written after the 2 protect registers are set
+func (mapper *Mapper005) PRGRAMMapRead(addr uint16) (uint32, bool) {
+	return uint32(mapper.prgRAMBank)*0x2000 | uint32(addr&0x1fff), true
+}
+
+func (mapper *Mapper005) PRGRAMMapWrite(addr uint16) (uint32, bool) {
+	return uint32(mapper.prgRAMBank)*0x2000 | uint32(addr&0x1fff), mapper.prgRAMWritable()
+}
+
+func (mapper *Mapper005) prgRAMWritable() bool {
+	return mapper.prgRAMProtect[0] == 0x02 && mapper.prgRAMProtect[1] == 0x01
+}
+
+// PRG-RAM can also be banked anywhere in 0x8000 - 0xdfff
+func (mapper *Mapper005) PRGRAMBankRead(addr uint16) (uint32, bool) {
+	bank, rom := mapper.prgBank(addr)
+	return (bank&0x07)*0x2000 | uint32(addr&0x1fff), !rom
+}
+
+func (mapper *Mapper005) PRGRAMBankWrite(addr uint16) (uint32, bool) {
+	bank, rom := mapper.prgBank(addr)
+	return (bank&0x07)*0x2000 | uint32(addr&0x1fff), !rom && mapper.prgRAMWritable()
+}
+
+// accesses the PPU memory
+// the background can use the split's bank or a bank from the extended attributes, otherwise the bank comes from set A or set B
+func (mapper *Mapper005) PPUMapRead(addr uint16) (uint32, bool) {
+	if addr >= 0x0000 && addr <= 0x1fff {
+		return mapper.chrAddress(addr), true
+	}
+
+	return 0x0000, false
+}
+
+func (mapper *Mapper005) PPUMapWrite(addr uint16) (uint32, bool) {
+	if addr >= 0x0000 && addr <= 0x1fff && mapper.CHRRAM {
+		return mapper.chrAddress(addr), true
+	}
+
+	return 0x0000, false
+}
+
+// gets the offset into CHR memory for a PPU address using the current banks
+func (mapper *Mapper005) chrAddress(addr uint16) uint32 {
+	size := uint32(0x2000) >> mapper.chrMode
+	chrSize := uint32(mapper.CHRBanks) * 0x2000
+	if chrSize == 0 {
+		chrSize = 0x2000
+	}
+
+	if mapper.rendering && !mapper.fetchingSprites {
+		//the split uses its own 4 KB bank, with the split's fine Y instead of the PPU's
+		if mapper.inSplit {
+			bank := uint32(mapper.splitBank) * 0x1000
+			return (bank | uint32(addr&0x0ff8) | uint32(mapper.splitY&0x07)) % chrSize
+		}
+		//extended attributes give each tile its own 4 KB bank
+		if mapper.exActive {
+			bank := uint32(mapper.exLatch&0x3f) | uint32(mapper.chrUpper)<<6
+			return (bank*0x1000 | uint32(addr&0x0fff)) % chrSize
+		}
+	}
+
+	//the register for a bank is the last one of the group for its size, the 4 set B registers cover 4 KB and repeat
+	slot := uint32(addr) / size
+	reg := (slot+1)*(8>>mapper.chrMode) - 1
+	var bank uint32
+	if mapper.useSetB() {
+		bank = uint32(mapper.chrB[reg&0x03])
+	} else {
+		bank = uint32(mapper.chrA[reg])
+	}
+
+	return (bank%(chrSize/size))*size | uint32(addr)%size
+}
+
+// with 8x16 sprites the sprites use set A and the background set B, otherwise everything uses whichever set was written last
+func (mapper *Mapper005) useSetB() bool {
+	if mapper.sprite16 && mapper.rendering {
+		return !mapper.fetchingSprites
+	}
+
+	return mapper.lastSetB
+}
+
+// each nametable can be either of the PPU's nametables, the ExRAM or the fill mode
+// the ExRAM and fill mode ones are handled by NameTableRead, since what they show depends on the ExRAM mode
+func (mapper *Mapper005) NameTablePages(ciram *[2][1024]uint8) [4][]uint8 {
+	var pages [4][]uint8
+	for table := range pages {
+		switch page := (mapper.nameTables >> (table * 2)) & 0x03; page {
+		case 0, 1:
+			pages[table] = ciram[page][:]
+		default:
+			pages[table] = mapper.exRAM[:]
+		}
+	}
+
+	return pages
+}
+
+// reads the nametables that use the ExRAM or fill mode, and the split and extended attributes while rendering
+func (mapper *Mapper005) NameTableRead(addr uint16) (uint8, bool) {
+	offset := addr & 0x03ff
+	attribute := offset >= 0x03c0
+	fetching := mapper.rendering && !mapper.fetchingSprites
+
+	if fetching && !attribute {
+		mapper.startTile()
+	}
+
+	//the split replaces the nametable with the ExRAM, scrolled by the split's own scroll
+	if fetching && mapper.inSplit {
+		column := uint16(mapper.tile-1) & 0x1f
+		row := uint16(mapper.splitY) / 8
+		if !attribute {
+			return mapper.exRAM[row*32+column], true
+		}
+		attrib := mapper.exRAM[0x03c0+(row/4)*8+column/4]
+		attrib >>= ((row & 0x02) << 1) | (column & 0x02)
+		return (attrib & 0x03) * 0x55, true
+	}
+
+	//extended attributes give each tile its own palette from the ExRAM
+	if fetching && mapper.exActive {
+		if !attribute {
+			mapper.exLatch = mapper.exRAM[offset]
+		} else {
+			//the PPU picks the quadrant itself, so the palette goes in all 4
+			return (mapper.exLatch >> 6) * 0x55, true
+		}
+	}
+
+	switch (mapper.nameTables >> (((addr - 0x2000) / 0x0400 & 0x03) * 2)) & 0x03 {
+	case 2:
+		if mapper.exRAMMode >= 2 {
+			return 0x00, true
+		}
+		return mapper.exRAM[offset], true
+	case 3:
+		if attribute {
+			return mapper.fillAttrib * 0x55, true
+		}
+		return mapper.fillTile, true
+	}
+
+	return 0x00, false
+}
+
+func (mapper *Mapper005) NameTableWrite(addr uint16, data uint8) bool {
+	switch (mapper.nameTables >> (((addr - 0x2000) / 0x0400 & 0x03) * 2)) & 0x03 {
+	case 2:
+		if mapper.exRAMMode < 2 {
+			mapper.exRAM[addr&0x03ff] = data
+		}
+		return true
+	case 3:
+		return true
+	}
+
+	return false
+}
+
+// a background tile is being fetched, works out if it's in the split and if it uses extended attributes
+func (mapper *Mapper005) startTile() {
+	mapper.tile++
+	column := mapper.tile - 1
+
+	mapper.inSplit = false
+	if mapper.splitControl&0x80 == 0x80 && mapper.exRAMMode < 2 {
+		splitTile := mapper.splitControl & 0x1f
+		if mapper.splitControl&0x40 == 0x40 {
+			mapper.inSplit = column >= splitTile && column < 32
+		} else {
+			mapper.inSplit = column < splitTile
+		}
+	}
+	mapper.exActive = mapper.exRAMMode == 1 && !mapper.inSplit
+}
+
+// counts the scanlines for the IRQ, the count starts over on the first rendered line of each frame
+func (mapper *Mapper005) PPUScanline(line int, rendering bool) {
+	mapper.rendering = rendering && line < 240
+	if !rendering || line < 0 || line >= 240 {
+		mapper.inFrame = false
+		mapper.fetchY = line
+		return
+	}
+
+	if !mapper.inFrame {
+		mapper.inFrame = true
+		mapper.scanline = 0
+	} else {
+		mapper.scanline++
+		if mapper.scanline == mapper.irqCompare {
+			mapper.irqPending = true
+		}
+	}
+	mapper.fetchY = line
+}
+
+// the background for the next line starts being fetched after the sprites, which is when the split's position moves down a line
+func (mapper *Mapper005) PPUFetchingSprites(sprites bool) {
+	mapper.fetchingSprites = sprites
+	if sprites {
+		return
+	}
+
+	mapper.tile = 0
+	line := mapper.fetchY + 1
+	if line < 0 || line >= 240 {
+		line = 0
+	}
+	splitY := int(mapper.splitScroll) + line
+	if splitY >= 240 {
+		splitY -= 240
+	}
+	mapper.splitY = uint8(splitY)
+}
+
+func (mapper *Mapper005) IRQ() bool {
+	return mapper.irqPending && mapper.irqEnable
+}
+
+// the MMC5 sees the CPU stop during a reset, which takes it out of the frame and clears the IRQ
+func (mapper *Mapper005) Reset() {
+	mapper.inFrame = false
+	mapper.irqPending = false
+}
+
+func (mapper *Mapper005) CPUClock() {
+	mapper.audio.clock()
+}
+
+func (mapper *Mapper005) AudioSample() float32 {
+	return mapper.audio.sample()
+}
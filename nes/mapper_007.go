@@ -0,0 +1,72 @@
+package nes
+
+//mapper for NES-AMROM, NES-ANROM, NES-AN1ROM, NES-AOROM
+
+type Mapper007 struct {
+	//how many banks of memory for each type of data
+	PRGBanks uint16
+	CHRBanks uint16
+	//if the board has CHR-RAM instead of CHR ROM, every AxROM board does
+	CHRRAM bool
+
+	//---M -PPP, which one-screen nametable is used, which 32 KB bank is at 0x8000
+	bankSelect uint8
+
+	//if writes to the ROM range are ANDed with the ROM's data
+	busConflicts bool
+}
+
+// accesses the CPU memory
+// in mapper 7 the whole 0x8000 - 0xffff range is one switchable 32 KB bank
+func (mapper *Mapper007) CPUMapRead(addr uint16) (uint32, bool) {
+	if addr >= 0x8000 && addr <= 0xffff {
+		//the bank count is in 16 KB banks, so halve it for 32 KB banks, a 16 KB ROM is just mirrored
+		banks := uint32(mapper.PRGBanks) / 2
+		if banks == 0 {
+			return uint32(addr & 0x3fff), true
+		}
+		return (uint32(mapper.bankSelect&0x07)%banks)*0x8000 | uint32(addr&0x7fff), true
+	}
+
+	return 0x0000, false
+}
+
+// writing anywhere in 0x8000 - 0xffff selects the PRG bank and the nametable
+func (mapper *Mapper007) CPUMapWrite(addr uint16, data uint8) (uint32, bool) {
+	if addr >= 0x8000 && addr <= 0xffff {
+		mapAddr, _ := mapper.CPUMapRead(addr)
+		mapper.bankSelect = data
+		return mapAddr, true
+	}
+
+	return 0x0000, false
+}
+
+// the CHR isn't banked, so it's mapped directly
+func (mapper *Mapper007) PPUMapRead(addr uint16) (uint32, bool) {
+	if addr >= 0x0000 && addr <= 0x1fff {
+		return uint32(addr), true
+	}
+
+	return 0x0000, false
+}
+
+func (mapper *Mapper007) PPUMapWrite(addr uint16) (uint32, bool) {
+	if addr >= 0x0000 && addr <= 0x1fff && mapper.CHRRAM {
+		return uint32(addr), true
+	}
+
+	return 0x0000, false
+}
+
+// AxROM has no mirroring from the header, all 4 nametables show one of the 2 physical tables
+func (mapper *Mapper007) Mirror() Mirror {
+	if mapper.bankSelect&0x10 != 0 {
+		return ONESCREEN_HI
+	}
+	return ONESCREEN_LO
+}
+
+func (mapper *Mapper007) BusConflicts() bool {
+	return mapper.busConflicts
+}
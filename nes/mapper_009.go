@@ -0,0 +1,129 @@
+package nes
+
+//mapper for the Nintendo MMC2, NES-PNROM, NES-PEEOROM
+
+type Mapper009 struct {
+	//how many banks of memory for each type of data
+	PRGBanks uint16
+	CHRBanks uint16
+
+	//which 8 KB bank is at 0x8000
+	prgBank uint8
+	//the 2 4 KB CHR banks for each pattern table, the first is used when the table's latch is 0xfd, the second when it's 0xfe
+	chrBanks [2][2]uint8
+	//the latch for each pattern table, false for 0xfd and true for 0xfe
+	latches [2]bool
+
+	mirror Mirror
+}
+
+func CreateMapper009(prgBanks uint16, chrBanks uint16, mirror Mirror) *Mapper009 {
+	mapper := Mapper009{
+		PRGBanks: prgBanks,
+		CHRBanks: chrBanks,
+		mirror:   mirror,
+	}
+
+	return &mapper
+}
+
+// accesses the CPU memory
+// in mapper 9 0x8000 - 0x9fff is a switchable 8 KB bank and 0xa000 - 0xffff is fixed to the last 3 8 KB banks
+func (mapper *Mapper009) CPUMapRead(addr uint16) (uint32, bool) {
+	if addr >= 0x8000 && addr <= 0x9fff {
+		return (uint32(mapper.prgBank)%(uint32(mapper.PRGBanks)*2))*0x2000 | uint32(addr&0x1fff), true
+	}
+	if addr >= 0xa000 && addr <= 0xffff {
+		//the last 3 8 KB banks of the ROM, counted from the end so a ROM smaller than 32 KB wraps around instead of going below 0
+		banks := uint32(mapper.PRGBanks) * 2
+		bank := (banks*3 - 3 + uint32(addr-0xa000)/0x2000) % banks
+		return bank*0x2000 | uint32(addr&0x1fff), true
+	}
+
+	return 0x0000, false
+}
+
+func (mapper *Mapper009) CPUMapWrite(addr uint16, data uint8) (uint32, bool) {
+	if addr >= 0x8000 && addr <= 0xffff {
+		mapAddr, _ := mapper.CPUMapRead(addr)
+		if addr >= 0xa000 && addr <= 0xafff {
+			mapper.prgBank = data & 0x0f
+		} else {
+			mapper.writeRegister(addr, data)
+		}
+		return mapAddr, true
+	}
+
+	return 0x0000, false
+}
+
+// the CHR and mirroring registers, which the MMC2 and MMC4 share
+// 0xb000 - 0xefff are the 4 CHR bank registers, 0xf000 - 0xffff is the mirroring
+func (mapper *Mapper009) writeRegister(addr uint16, data uint8) {
+	switch {
+	case addr >= 0xb000 && addr <= 0xbfff:
+		mapper.chrBanks[0][0] = data & 0x1f
+	case addr >= 0xc000 && addr <= 0xcfff:
+		mapper.chrBanks[0][1] = data & 0x1f
+	case addr >= 0xd000 && addr <= 0xdfff:
+		mapper.chrBanks[1][0] = data & 0x1f
+	case addr >= 0xe000 && addr <= 0xefff:
+		mapper.chrBanks[1][1] = data & 0x1f
+	case addr >= 0xf000:
+		if data&1 == 0 {
+			mapper.mirror = VERTICAL
+		} else {
+			mapper.mirror = HORIZONTAL
+		}
+	}
+}
+
+// accesses the PPU memory
+// each 4 KB pattern table uses one of its 2 banks depending on its latch
+func (mapper *Mapper009) PPUMapRead(addr uint16) (uint32, bool) {
+	if addr >= 0x0000 && addr <= 0x1fff {
+		table := (addr & 0x1000) >> 12
+		latch := 0
+		if mapper.latches[table] {
+			latch = 1
+		}
+
+		//there are 2 4 KB banks in each 8 KB bank
+		banks := uint32(mapper.CHRBanks) * 2
+		return (uint32(mapper.chrBanks[table][latch])%banks)*0x1000 | uint32(addr&0x0fff), true
+	}
+
+	return 0x0000, false
+}
+
+// the MMC2 only has CHR ROM, so it can't be written to
+func (mapper *Mapper009) PPUMapWrite(addr uint16) (uint32, bool) {
+	return 0x0000, false
+}
+
+func (mapper *Mapper009) Mirror() Mirror {
+	return mapper.mirror
+}
+
+// the latches flip when the PPU fetches the second bitplane of tile 0xfd or 0xfe, after the fetch, so the tile itself is drawn with the old bank
+// the MMC2 only watches the first row of the tile for the first pattern table, but the whole tile for the second
+func (mapper *Mapper009) PPUAddress(addr uint16) {
+	switch {
+	case addr == 0x0fd8:
+		mapper.latches[0] = false
+	case addr == 0x0fe8:
+		mapper.latches[0] = true
+	default:
+		mapper.watchLatch1(addr)
+	}
+}
+
+// the second pattern table's latch works the same way on the MMC2 and MMC4
+func (mapper *Mapper009) watchLatch1(addr uint16) {
+	switch {
+	case addr >= 0x1fd8 && addr <= 0x1fdf:
+		mapper.latches[1] = false
+	case addr >= 0x1fe8 && addr <= 0x1fef:
+		mapper.latches[1] = true
+	}
+}
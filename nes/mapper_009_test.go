@@ -0,0 +1,29 @@
+package nes
+
+import "testing"
+
+func TestMapper009Banks(t *testing.T) {
+	create := func() Mapper {
+		return CreateMapper009(8, 16, VERTICAL)
+	}
+
+	runBankTests(t, create, []bankTest{
+		{name: "power on prg", addr: 0x8123, want: 0x00123},
+		{name: "prg bank", writes: []registerWrite{{0xa000, 0x05}}, addr: 0x9fff, want: 0x0bfff},
+		{name: "fixed banks", addr: 0xa000, want: 0x1a000},
+		{name: "last bank", addr: 0xfffc, want: 0x1fffc},
+	})
+}
+
+func TestMapper009SmallPRG(t *testing.T) {
+	//with only 16 KB the last 3 8 KB banks wrap around to 1, 0, 1
+	create := func() Mapper {
+		return CreateMapper009(1, 16, VERTICAL)
+	}
+
+	runBankTests(t, create, []bankTest{
+		{name: "0xa000", addr: 0xa123, want: 0x02123},
+		{name: "0xc000", addr: 0xc123, want: 0x00123},
+		{name: "0xe000", addr: 0xfffc, want: 0x03ffc},
+	})
+}
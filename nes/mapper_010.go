@@ -0,0 +1,54 @@
+package nes
+
+//mapper for the Nintendo MMC4, HVC-FJROM, HVC-FKROM
+//the MMC4 is the MMC2 with 16 KB PRG banks, PRG-RAM, and a latch that watches the whole tile in both pattern tables
+
+type Mapper010 struct {
+	Mapper009
+}
+
+func CreateMapper010(prgBanks uint16, chrBanks uint16, mirror Mirror) *Mapper010 {
+	mapper := Mapper010{
+		Mapper009: *CreateMapper009(prgBanks, chrBanks, mirror),
+	}
+
+	return &mapper
+}
+
+// accesses the CPU memory
+// in mapper 10 0x8000 - 0xbfff is a switchable 16 KB bank and 0xc000 - 0xffff is fixed to the last bank
+func (mapper *Mapper010) CPUMapRead(addr uint16) (uint32, bool) {
+	if addr >= 0x8000 && addr <= 0xbfff {
+		return (uint32(mapper.prgBank)%uint32(mapper.PRGBanks))*0x4000 | uint32(addr&0x3fff), true
+	}
+	if addr >= 0xc000 && addr <= 0xffff {
+		return uint32(mapper.PRGBanks-1)*0x4000 | uint32(addr&0x3fff), true
+	}
+
+	return 0x0000, false
+}
+
+func (mapper *Mapper010) CPUMapWrite(addr uint16, data uint8) (uint32, bool) {
+	if addr >= 0x8000 && addr <= 0xffff {
+		mapAddr, _ := mapper.CPUMapRead(addr)
+		if addr >= 0xa000 && addr <= 0xafff {
+			mapper.prgBank = data & 0x0f
+		} else {
+			mapper.writeRegister(addr, data)
+		}
+		return mapAddr, true
+	}
+
+	return 0x0000, false
+}
+
+func (mapper *Mapper010) PPUAddress(addr uint16) {
+	switch {
+	case addr >= 0x0fd8 && addr <= 0x0fdf:
+		mapper.latches[0] = false
+	case addr >= 0x0fe8 && addr <= 0x0fef:
+		mapper.latches[0] = true
+	default:
+		mapper.watchLatch1(addr)
+	}
+}
@@ -0,0 +1,25 @@
+package nes
+
+import "testing"
+
+func TestMapper011Banks(t *testing.T) {
+	create := func() Mapper {
+		return &Mapper011{PRGBanks: 8, CHRBanks: 4}
+	}
+
+	runBankTests(t, create, []bankTest{
+		{name: "power on prg", addr: 0x8000, want: 0x00000},
+		{name: "power on chr", ppu: true, addr: 0x1234, want: 0x01234},
+		{name: "prg bank", writes: []registerWrite{{0x8000, 0x02}}, addr: 0x8123, want: 0x10123},
+		{name: "prg bank top", writes: []registerWrite{{0xffff, 0x03}}, addr: 0xffff, want: 0x1ffff},
+		{name: "chr bank", writes: []registerWrite{{0x8000, 0x30}}, ppu: true, addr: 0x0123, want: 0x06123},
+		{name: "chr bank wraps", writes: []registerWrite{{0x8000, 0x50}}, ppu: true, addr: 0x0000, want: 0x02000},
+		{name: "lockout bits ignored", writes: []registerWrite{{0x8000, 0x0c}}, addr: 0x8000, want: 0x00000},
+		{name: "below rom ignored", writes: []registerWrite{{0x6000, 0x01}}, addr: 0x8000, want: 0x00000},
+	})
+
+	//a 16 KB ROM is mirrored into both halves
+	runBankTests(t, func() Mapper { return &Mapper011{PRGBanks: 1, CHRBanks: 1} }, []bankTest{
+		{name: "16 KB mirrored", writes: []registerWrite{{0x8000, 0x01}}, addr: 0xc010, want: 0x00010},
+	})
+}
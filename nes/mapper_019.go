@@ -0,0 +1,217 @@
+package nes
+
+//mapper for the Namco 163 (and the Namco 129, which is the same without the sound)
+
+type Mapper019 struct {
+	//how many banks of memory for each type of data, CHR counts CHR-RAM
+	PRGBanks uint16
+	CHRBanks uint16
+	//if the board has CHR-RAM instead of CHR ROM
+	CHRRAM bool
+	//the CHR memory, the nametables can be pointed at it
+	CHRMemory []uint8
+
+	//the 3 switchable 8 KB PRG banks
+	prgBanks [3]uint8
+	//the 8 1 KB CHR banks, then the 4 nametables, nametable banks 0xe0 and up are the PPU's own nametables
+	chrBanks       [8]uint8
+	nameTableBanks [4]uint8
+	soundOff       bool
+
+	//WWWW PPPP, the PRG-RAM is writable when the W bits are 0100, and each P bit protects 2 KB of it
+	prgRAMProtect uint8
+
+	//15 bit counter that counts up every CPU cycle, firing when it reaches 0x7fff
+	irqCounter uint16
+	irqEnable  bool
+	irqActive  bool
+
+	audio n163Audio
+}
+
+func CreateMapper019(prgBanks uint16, chrBanks uint16, chrRAM bool, chr []uint8) *Mapper019 {
+	mapper := Mapper019{
+		PRGBanks:  prgBanks,
+		CHRBanks:  chrBanks,
+		CHRRAM:    chrRAM,
+		CHRMemory: chr,
+	}
+	//the nametables start out as the usual vertical layout
+	mapper.nameTableBanks = [4]uint8{0xe0, 0xe1, 0xe0, 0xe1}
+
+	return &mapper
+}
+
+// accesses the CPU memory
+// in mapper 19 there are 4 8 KB banks, the first 3 are switchable and the last is fixed to the last bank
+func (mapper *Mapper019) CPUMapRead(addr uint16) (uint32, bool) {
+	if addr >= 0x8000 && addr <= 0xffff {
+		banks := uint32(mapper.PRGBanks) * 2
+
+		bank := banks - 1
+		if slot := (addr - 0x8000) / 0x2000; slot < 3 {
+			bank = uint32(mapper.prgBanks[slot])
+		}
+
+		return (bank%banks)*0x2000 | uint32(addr&0x1fff), true
+	}
+
+	return 0x0000, false
+}
+
+// each register takes up 0x800 bytes, the sound RAM and IRQ counter are below the ROM
+func (mapper *Mapper019) CPUMapWrite(addr uint16, data uint8) (uint32, bool) {
+	switch {
+	case addr >= 0x4800 && addr <= 0x4fff:
+		mapper.audio.access(true, data)
+		return 0x0000, true
+	case addr >= 0x5000 && addr <= 0x57ff:
+		mapper.irqCounter = (mapper.irqCounter & 0x7f00) | uint16(data)
+		mapper.irqActive = false
+		return 0x0000, true
+	case addr >= 0x5800 && addr <= 0x5fff:
+		mapper.irqCounter = (mapper.irqCounter & 0x00ff) | uint16(data&0x7f)<<8
+		mapper.irqEnable = data&0x80 == 0x80
+		mapper.irqActive = false
+		return 0x0000, true
+	case addr < 0x8000:
+		return 0x0000, false
+	}
+
+	mapAddr, _ := mapper.CPUMapRead(addr)
+
+	switch reg := (addr - 0x8000) / 0x0800; {
+	case reg < 8:
+		mapper.chrBanks[reg] = data
+	case reg < 12:
+		mapper.nameTableBanks[reg-8] = data
+	case reg == 12:
+		mapper.prgBanks[0] = data & 0x3f
+		mapper.soundOff = data&0x40 == 0x40
+	case reg == 13:
+		//the top 2 bits are if CHR banks 0xe0 and up use the nametables, which isn't supported
+		mapper.prgBanks[1] = data & 0x3f
+	case reg == 14:
+		mapper.prgBanks[2] = data & 0x3f
+	default:
+		mapper.audio.writeAddress(data)
+		mapper.prgRAMProtect = data
+	}
+
+	return mapAddr, true
+}
+
+// the sound RAM and the IRQ counter can be read back
+func (mapper *Mapper019) CPUReadData(addr uint16) (uint8, bool) {
+	switch {
+	case addr >= 0x4800 && addr <= 0x4fff:
+		return mapper.audio.access(false, 0), true
+	case addr >= 0x5000 && addr <= 0x57ff:
+		return uint8(mapper.irqCounter), true
+	case addr >= 0x5800 && addr <= 0x5fff:
+		high := uint8(mapper.irqCounter >> 8)
+		if mapper.irqEnable {
+			high |= 0x80
+		}
+		return high, true
+	}
+
+	return 0x0000, false
+}
+
+// accesses the PPU memory, 8 1 KB banks
+// banks 0xe0 and up are meant to use the PPU's nametables as patterns, which isn't supported, so they use CHR memory like the other banks
+func (mapper *Mapper019) PPUMapRead(addr uint16) (uint32, bool) {
+	if addr >= 0x0000 && addr <= 0x1fff {
+		return mapper.chrAddress(mapper.chrBanks[addr/0x0400], addr), true
+	}
+
+	return 0x0000, false
+}
+
+func (mapper *Mapper019) PPUMapWrite(addr uint16) (uint32, bool) {
+	if addr >= 0x0000 && addr <= 0x1fff && mapper.CHRRAM {
+		return mapper.chrAddress(mapper.chrBanks[addr/0x0400], addr), true
+	}
+
+	return 0x0000, false
+}
+
+func (mapper *Mapper019) chrAddress(bank uint8, addr uint16) uint32 {
+	//there are 8 1 KB banks in each 8 KB bank
+	banks := uint32(mapper.CHRBanks) * 8
+	if banks == 0 {
+		banks = 8
+	}
+
+	return (uint32(bank)%banks)*0x0400 | uint32(addr&0x03ff)
+}
+
+// each nametable can be either of the PPU's nametables, or a 1 KB bank of CHR memory
+func (mapper *Mapper019) NameTablePages(ciram *[2][1024]uint8) [4][]uint8 {
+	var pages [4][]uint8
+	for table, bank := range mapper.nameTableBanks {
+		if bank >= 0xe0 {
+			pages[table] = ciram[bank&1][:]
+		} else {
+			start := mapper.chrAddress(bank, 0)
+			pages[table] = mapper.CHRMemory[start : start+0x0400]
+		}
+	}
+
+	return pages
+}
+
+// the nametables using CHR ROM, which can't be written, CHR-RAM is used through the pages like the PPU's own nametables
+func (mapper *Mapper019) NameTableRead(addr uint16) (uint8, bool) {
+	bank := mapper.nameTableBanks[(addr-0x2000)/0x0400&0x03]
+	if bank >= 0xe0 || mapper.CHRRAM {
+		return 0x00, false
+	}
+
+	return mapper.CHRMemory[mapper.chrAddress(bank, addr)], true
+}
+
+func (mapper *Mapper019) NameTableWrite(addr uint16, data uint8) bool {
+	bank := mapper.nameTableBanks[(addr-0x2000)/0x0400&0x03]
+	return bank < 0xe0 && !mapper.CHRRAM
+}
+
+// the PRG-RAM is write protected in 2 KB pieces
+func (mapper *Mapper019) PRGRAMMapRead(addr uint16) (uint32, bool) {
+	return uint32(addr - 0x6000), true
+}
+
+func (mapper *Mapper019) PRGRAMMapWrite(addr uint16) (uint32, bool) {
+	piece := (addr - 0x6000) / 0x0800
+	writable := mapper.prgRAMProtect&0xf0 == 0x40 && mapper.prgRAMProtect&(1<<piece) == 0
+	return uint32(addr - 0x6000), writable
+}
+
+func (mapper *Mapper019) IRQ() bool {
+	return mapper.irqActive
+}
+
+func (mapper *Mapper019) CPUClock() {
+	if mapper.irqEnable && !mapper.irqActive {
+		if mapper.irqCounter == 0x7fff {
+			mapper.irqActive = true
+		} else {
+			mapper.irqCounter++
+		}
+	}
+
+	mapper.audio.clock()
+}
+
+func (mapper *Mapper019) AudioSample() float32 {
+	if mapper.soundOff {
+		return 0
+	}
+	return mapper.audio.sample()
+}
+
+// the sound RAM is battery backed on the boards with a battery, and games keep their saves in it
+func (mapper *Mapper019) SaveRAM() []uint8 {
+	return mapper.audio.ram[:]
+}
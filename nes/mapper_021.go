@@ -0,0 +1,236 @@
+package nes
+
+//mapper for the Konami VRC2 and VRC4, also used for mappers 22, 23 and 25
+//the boards only differ in which CPU address lines are connected to the chip's 2 register select pins, and if it's a VRC2 or a VRC4
+//the VRC2 doesn't have the IRQ, the PRG swap mode or one-screen mirroring
+
+type Mapper021 struct {
+	//how many banks of memory for each type of data, CHR counts CHR-RAM
+	PRGBanks uint16
+	CHRBanks uint16
+	//if the board has CHR-RAM instead of CHR ROM
+	CHRRAM bool
+
+	//which CPU address lines are connected to the register select pins, when the submapper isn't known both possible lines are used
+	a0Lines uint16
+	a1Lines uint16
+	vrc4    bool
+	//the VRC2a ignores the lowest bit of the CHR banks
+	chrShift uint8
+
+	//the 2 switchable 8 KB PRG banks, and if the first is at 0xc000 instead of 0x8000
+	prgBanks [2]uint8
+	prgSwap  bool
+	//the 8 1 KB CHR banks, each written 4 bits at a time
+	chrBanks [8]uint16
+
+	mirror Mirror
+	irq    vrcIRQ
+}
+
+// sets up the wiring for the board from the mapper and submapper numbers
+func CreateMapper021(mapperID uint16, submapperID uint8, prgBanks uint16, chrBanks uint16, chrRAM bool, mirror Mirror) *Mapper021 {
+	mapper := Mapper021{
+		PRGBanks: prgBanks,
+		CHRBanks: chrBanks,
+		CHRRAM:   chrRAM,
+		mirror:   mirror,
+		vrc4:     true,
+	}
+
+	switch mapperID {
+	case 21:
+		switch submapperID {
+		case 1: //VRC4a
+			mapper.a0Lines, mapper.a1Lines = 0x02, 0x04
+		case 2: //VRC4c
+			mapper.a0Lines, mapper.a1Lines = 0x40, 0x80
+		default:
+			mapper.a0Lines, mapper.a1Lines = 0x42, 0x84
+		}
+	case 22: //VRC2a
+		mapper.a0Lines, mapper.a1Lines = 0x02, 0x01
+		mapper.vrc4 = false
+		mapper.chrShift = 1
+	case 23:
+		switch submapperID {
+		case 1: //VRC4f
+			mapper.a0Lines, mapper.a1Lines = 0x01, 0x02
+		case 2: //VRC4e
+			mapper.a0Lines, mapper.a1Lines = 0x04, 0x08
+		case 3: //VRC2b
+			mapper.a0Lines, mapper.a1Lines = 0x01, 0x02
+			mapper.vrc4 = false
+		default:
+			mapper.a0Lines, mapper.a1Lines = 0x05, 0x0a
+		}
+	case 25:
+		switch submapperID {
+		case 1: //VRC4b
+			mapper.a0Lines, mapper.a1Lines = 0x02, 0x01
+		case 2: //VRC4d
+			mapper.a0Lines, mapper.a1Lines = 0x08, 0x04
+		case 3: //VRC2c
+			mapper.a0Lines, mapper.a1Lines = 0x02, 0x01
+			mapper.vrc4 = false
+		default:
+			mapper.a0Lines, mapper.a1Lines = 0x0a, 0x05
+		}
+	}
+
+	return &mapper
+}
+
+// accesses the CPU memory
+// in mapper 21 there are 4 8 KB banks, 0xa000 is switchable, 0xe000 is fixed to the last bank, and the swap mode picks which of 0x8000 and 0xc000 is switchable and which is the second last bank
+func (mapper *Mapper021) CPUMapRead(addr uint16) (uint32, bool) {
+	if addr >= 0x8000 && addr <= 0xffff {
+		banks := uint32(mapper.PRGBanks) * 2
+		secondLast := banks - 2
+
+		var bank uint32
+		switch (addr - 0x8000) / 0x2000 {
+		case 0:
+			if mapper.prgSwap {
+				bank = secondLast
+			} else {
+				bank = uint32(mapper.prgBanks[0])
+			}
+		case 1:
+			bank = uint32(mapper.prgBanks[1])
+		case 2:
+			if mapper.prgSwap {
+				bank = uint32(mapper.prgBanks[0])
+			} else {
+				bank = secondLast
+			}
+		case 3:
+			bank = banks - 1
+		}
+
+		return (bank%banks)*0x2000 | uint32(addr&0x1fff), true
+	}
+
+	return 0x0000, false
+}
+
+// the registers are in groups of 4 every 0x1000 bytes, which of the 4 comes from the 2 address lines the board connects
+func (mapper *Mapper021) CPUMapWrite(addr uint16, data uint8) (uint32, bool) {
+	if addr < 0x8000 {
+		return 0x0000, false
+	}
+
+	mapAddr, _ := mapper.CPUMapRead(addr)
+
+	reg := uint16(0)
+	if addr&mapper.a0Lines != 0 {
+		reg |= 1
+	}
+	if addr&mapper.a1Lines != 0 {
+		reg |= 2
+	}
+
+	switch addr & 0xf000 {
+	case 0x8000:
+		mapper.prgBanks[0] = data & 0x1f
+	case 0x9000:
+		mapper.writeControl(reg, data)
+	case 0xa000:
+		mapper.prgBanks[1] = data & 0x1f
+	case 0xb000, 0xc000, 0xd000, 0xe000:
+		//each register is half of a bank, the first 2 are the low and high half of one bank, the next 2 the next bank
+		bank := (addr-0xb000)/0x1000*2 + reg>>1
+		if reg&1 == 0 {
+			mapper.chrBanks[bank] = (mapper.chrBanks[bank] & 0x1f0) | uint16(data&0x0f)
+		} else {
+			mapper.chrBanks[bank] = (mapper.chrBanks[bank] & 0x00f) | uint16(data&0x1f)<<4
+		}
+	case 0xf000:
+		if mapper.vrc4 {
+			mapper.writeIRQ(reg, data)
+		}
+	}
+
+	return mapAddr, true
+}
+
+// 0x9000 is the mirroring, on the VRC4 the third and fourth registers are the PRG swap mode
+func (mapper *Mapper021) writeControl(reg uint16, data uint8) {
+	if !mapper.vrc4 {
+		if data&1 == 0 {
+			mapper.mirror = VERTICAL
+		} else {
+			mapper.mirror = HORIZONTAL
+		}
+		return
+	}
+
+	if reg >= 2 {
+		mapper.prgSwap = data&0x02 == 0x02
+		return
+	}
+	switch data & 0x03 {
+	case 0:
+		mapper.mirror = VERTICAL
+	case 1:
+		mapper.mirror = HORIZONTAL
+	case 2:
+		mapper.mirror = ONESCREEN_LO
+	case 3:
+		mapper.mirror = ONESCREEN_HI
+	}
+}
+
+// 0xf000 and 0xf001 are the low and high 4 bits of the latch, then the control and acknowledge
+func (mapper *Mapper021) writeIRQ(reg uint16, data uint8) {
+	switch reg {
+	case 0:
+		mapper.irq.latch = (mapper.irq.latch & 0xf0) | data&0x0f
+	case 1:
+		mapper.irq.latch = (mapper.irq.latch & 0x0f) | data<<4
+	case 2:
+		mapper.irq.writeControl(data)
+	case 3:
+		mapper.irq.acknowledge()
+	}
+}
+
+// accesses the PPU memory, 8 1 KB banks
+func (mapper *Mapper021) PPUMapRead(addr uint16) (uint32, bool) {
+	if addr >= 0x0000 && addr <= 0x1fff {
+		return mapper.chrAddress(addr), true
+	}
+
+	return 0x0000, false
+}
+
+func (mapper *Mapper021) PPUMapWrite(addr uint16) (uint32, bool) {
+	if addr >= 0x0000 && addr <= 0x1fff && mapper.CHRRAM {
+		return mapper.chrAddress(addr), true
+	}
+
+	return 0x0000, false
+}
+
+func (mapper *Mapper021) chrAddress(addr uint16) uint32 {
+	//there are 8 1 KB banks in each 8 KB bank
+	banks := uint32(mapper.CHRBanks) * 8
+	if banks == 0 {
+		banks = 8
+	}
+
+	bank := uint32(mapper.chrBanks[addr/0x0400] >> mapper.chrShift)
+	return (bank%banks)*0x0400 | uint32(addr&0x03ff)
+}
+
+func (mapper *Mapper021) Mirror() Mirror {
+	return mapper.mirror
+}
+
+func (mapper *Mapper021) IRQ() bool {
+	return mapper.irq.active
+}
+
+func (mapper *Mapper021) CPUClock() {
+	mapper.irq.clock()
+}
@@ -0,0 +1,191 @@
+package nes
+
+//mapper for the Konami VRC6, also used for mapper 26 which swaps the 2 register select lines
+
+type Mapper024 struct {
+	//how many banks of memory for each type of data, CHR counts CHR-RAM
+	PRGBanks uint16
+	CHRBanks uint16
+	//if the board has CHR-RAM instead of CHR ROM
+	CHRRAM bool
+
+	//which CPU address lines are connected to the 2 register select pins
+	a0Line uint16
+	a1Line uint16
+
+	//the 16 KB bank at 0x8000 and the 8 KB bank at 0xc000
+	prgBank16 uint8
+	prgBank8  uint8
+	//the 8 1 KB CHR registers, how they're used depends on the banking mode
+	chrBanks [8]uint8
+	//W-PN MMDD, PRG-RAM enable, CHR A10 policy, nametable source, mirroring, CHR banking mode
+	control uint8
+
+	irq   vrcIRQ
+	audio vrc6Audio
+}
+
+func CreateMapper024(mapperID uint16, prgBanks uint16, chrBanks uint16, chrRAM bool) *Mapper024 {
+	mapper := Mapper024{
+		PRGBanks: prgBanks,
+		CHRBanks: chrBanks,
+		CHRRAM:   chrRAM,
+		a0Line:   0x01,
+		a1Line:   0x02,
+	}
+	if mapperID == 26 {
+		mapper.a0Line, mapper.a1Line = 0x02, 0x01
+	}
+
+	return &mapper
+}
+
+// accesses the CPU memory
+// in mapper 24 0x8000 - 0xbfff is a switchable 16 KB bank, 0xc000 - 0xdfff is a switchable 8 KB bank, and 0xe000 - 0xffff is fixed to the last 8 KB
+func (mapper *Mapper024) CPUMapRead(addr uint16) (uint32, bool) {
+	banks := uint32(mapper.PRGBanks) * 2
+	switch {
+	case addr >= 0x8000 && addr <= 0xbfff:
+		return (uint32(mapper.prgBank16)%uint32(mapper.PRGBanks))*0x4000 | uint32(addr&0x3fff), true
+	case addr >= 0xc000 && addr <= 0xdfff:
+		return (uint32(mapper.prgBank8)%banks)*0x2000 | uint32(addr&0x1fff), true
+	case addr >= 0xe000:
+		return (banks-1)*0x2000 | uint32(addr&0x1fff), true
+	}
+
+	return 0x0000, false
+}
+
+// the registers are in groups of 4 every 0x1000 bytes, which of the 4 comes from the 2 address lines the board connects
+func (mapper *Mapper024) CPUMapWrite(addr uint16, data uint8) (uint32, bool) {
+	if addr < 0x8000 {
+		return 0x0000, false
+	}
+
+	mapAddr, _ := mapper.CPUMapRead(addr)
+
+	reg := uint16(0)
+	if addr&mapper.a0Line != 0 {
+		reg |= 1
+	}
+	if addr&mapper.a1Line != 0 {
+		reg |= 2
+	}
+
+	switch addr & 0xf000 {
+	case 0x8000:
+		mapper.prgBank16 = data & 0x0f
+	case 0x9000:
+		if reg == 3 {
+			mapper.audio.frequencyControl = data
+		} else {
+			mapper.audio.pulses[0].write(reg, data)
+		}
+	case 0xa000:
+		if reg < 3 {
+			mapper.audio.pulses[1].write(reg, data)
+		}
+	case 0xb000:
+		if reg == 3 {
+			mapper.control = data
+		} else {
+			mapper.audio.saw.write(reg, data)
+		}
+	case 0xc000:
+		mapper.prgBank8 = data & 0x1f
+	case 0xd000:
+		mapper.chrBanks[reg] = data
+	case 0xe000:
+		mapper.chrBanks[4+reg] = data
+	case 0xf000:
+		switch reg {
+		case 0:
+			mapper.irq.latch = data
+		case 1:
+			mapper.irq.writeControl(data)
+		case 2:
+			mapper.irq.acknowledge()
+		}
+	}
+
+	return mapAddr, true
+}
+
+// accesses the PPU memory
+// mode 0 is 8 1 KB banks, mode 1 is 4 2 KB banks, and modes 2 and 3 are 4 1 KB banks then 2 2 KB banks
+func (mapper *Mapper024) PPUMapRead(addr uint16) (uint32, bool) {
+	if addr >= 0x0000 && addr <= 0x1fff {
+		return mapper.chrAddress(addr), true
+	}
+
+	return 0x0000, false
+}
+
+func (mapper *Mapper024) PPUMapWrite(addr uint16) (uint32, bool) {
+	if addr >= 0x0000 && addr <= 0x1fff && mapper.CHRRAM {
+		return mapper.chrAddress(addr), true
+	}
+
+	return 0x0000, false
+}
+
+func (mapper *Mapper024) chrAddress(addr uint16) uint32 {
+	//there are 8 1 KB banks in each 8 KB bank
+	banks := uint32(mapper.CHRBanks) * 8
+	if banks == 0 {
+		banks = 8
+	}
+
+	slot := addr / 0x0400
+	var bank uint32
+	switch mapper.control & 0x03 {
+	case 0:
+		bank = uint32(mapper.chrBanks[slot])
+	case 1:
+		//2 KB banks use the register's bank as the first 1 KB and the next bank as the second
+		bank = uint32(mapper.chrBanks[slot/2])<<1 | uint32(slot&1)
+	default:
+		if slot < 4 {
+			bank = uint32(mapper.chrBanks[slot])
+		} else {
+			bank = uint32(mapper.chrBanks[4+(slot-4)/2])<<1 | uint32(slot&1)
+		}
+	}
+
+	return (bank%banks)*0x0400 | uint32(addr&0x03ff)
+}
+
+// the mirroring bits mean vertical, horizontal, and the 2 one-screen layouts in the usual CHR banking mode
+func (mapper *Mapper024) Mirror() Mirror {
+	switch (mapper.control >> 2) & 0x03 {
+	case 0:
+		return VERTICAL
+	case 1:
+		return HORIZONTAL
+	case 2:
+		return ONESCREEN_LO
+	}
+	return ONESCREEN_HI
+}
+
+// the PRG-RAM is only there when it's enabled
+func (mapper *Mapper024) PRGRAMMapRead(addr uint16) (uint32, bool) {
+	return uint32(addr - 0x6000), mapper.control&0x80 == 0x80
+}
+
+func (mapper *Mapper024) PRGRAMMapWrite(addr uint16) (uint32, bool) {
+	return uint32(addr - 0x6000), mapper.control&0x80 == 0x80
+}
+
+func (mapper *Mapper024) IRQ() bool {
+	return mapper.irq.active
+}
+
+func (mapper *Mapper024) CPUClock() {
+	mapper.irq.clock()
+	mapper.audio.clock()
+}
+
+func (mapper *Mapper024) AudioSample() float32 {
+	return mapper.audio.sample()
+}
@@ -0,0 +1,116 @@
+package nes
+
+//mapper for NES-BNROM and the AVE NINA-001, 2 unrelated boards that share the number
+//BNROM has a 32 KB PRG bank register over the whole ROM, NINA-001 has its registers at the top of the PRG-RAM and 2 4 KB CHR banks
+
+type Mapper034 struct {
+	//how many banks of memory for each type of data, CHR counts CHR-RAM
+	PRGBanks uint16
+	CHRBanks uint16
+	//if the board has CHR-RAM instead of CHR ROM
+	CHRRAM bool
+
+	//if the board is a NINA-001 instead of a BNROM
+	nina bool
+
+	//which 32 KB PRG bank is at 0x8000
+	prgBank uint8
+	//the 2 4 KB CHR banks on the NINA-001
+	chrBanks [2]uint8
+}
+
+// the submapper says which board it is, without one the NINA-001 is the one with more than 8 KB of CHR ROM
+func CreateMapper034(submapperID uint8, prgBanks uint16, chrBanks uint16, chrRAM bool) *Mapper034 {
+	mapper := Mapper034{
+		PRGBanks: prgBanks,
+		CHRBanks: chrBanks,
+		CHRRAM:   chrRAM,
+	}
+
+	switch submapperID {
+	case 1:
+		mapper.nina = true
+	case 2:
+		mapper.nina = false
+	default:
+		mapper.nina = !chrRAM && chrBanks > 1
+	}
+	//the NINA-001 starts with the banks in order
+	mapper.chrBanks[1] = 1
+
+	return &mapper
+}
+
+// accesses the CPU memory
+// in mapper 34 the whole 0x8000 - 0xffff range is one switchable 32 KB bank
+func (mapper *Mapper034) CPUMapRead(addr uint16) (uint32, bool) {
+	if addr >= 0x8000 && addr <= 0xffff {
+		//the bank count is in 16 KB banks, so halve it for 32 KB banks, a 16 KB ROM is just mirrored
+		banks := uint32(mapper.PRGBanks) / 2
+		if banks == 0 {
+			return uint32(addr & 0x3fff), true
+		}
+		return (uint32(mapper.prgBank)%banks)*0x8000 | uint32(addr&0x7fff), true
+	}
+
+	return 0x0000, false
+}
+
+// BNROM takes writes anywhere in 0x8000 - 0xffff, NINA-001 takes them at 0x7ffd - 0x7fff
+func (mapper *Mapper034) CPUMapWrite(addr uint16, data uint8) (uint32, bool) {
+	if mapper.nina {
+		//the registers sit on top of the RAM, so the write is left to go to the RAM as well
+		switch addr {
+		case 0x7ffd:
+			mapper.prgBank = data & 0x01
+		case 0x7ffe:
+			mapper.chrBanks[0] = data & 0x0f
+		case 0x7fff:
+			mapper.chrBanks[1] = data & 0x0f
+		}
+		if addr >= 0x8000 && addr <= 0xffff {
+			return mapper.CPUMapRead(addr)
+		}
+		return 0x0000, false
+	}
+
+	if addr >= 0x8000 && addr <= 0xffff {
+		mapAddr, _ := mapper.CPUMapRead(addr)
+		mapper.prgBank = data
+		return mapAddr, true
+	}
+
+	return 0x0000, false
+}
+
+// accesses the PPU memory, BNROM's CHR isn't banked, the NINA-001 has 2 4 KB banks
+func (mapper *Mapper034) PPUMapRead(addr uint16) (uint32, bool) {
+	if addr >= 0x0000 && addr <= 0x1fff {
+		return mapper.chrAddress(addr), true
+	}
+
+	return 0x0000, false
+}
+
+func (mapper *Mapper034) PPUMapWrite(addr uint16) (uint32, bool) {
+	if addr >= 0x0000 && addr <= 0x1fff && mapper.CHRRAM {
+		return mapper.chrAddress(addr), true
+	}
+
+	return 0x0000, false
+}
+
+func (mapper *Mapper034) chrAddress(addr uint16) uint32 {
+	if !mapper.nina {
+		return uint32(addr)
+	}
+
+	//there are 2 4 KB banks in each 8 KB bank
+	banks := uint32(mapper.CHRBanks) * 2
+	return (uint32(mapper.chrBanks[addr/0x1000])%banks)*0x1000 | uint32(addr&0x0fff)
+}
+
+// only BNROM has bus conflicts, the NINA-001's registers aren't in the ROM range
+func (mapper *Mapper034) BusConflicts() bool {
+	return !mapper.nina
+}
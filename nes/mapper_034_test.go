@@ -0,0 +1,62 @@
+package nes
+
+import "testing"
+
+func TestMapper034BNROM(t *testing.T) {
+	//no submapper and CHR-RAM is BNROM
+	create := func() Mapper {
+		return CreateMapper034(0, 8, 1, true)
+	}
+
+	runBankTests(t, create, []bankTest{
+		{name: "power on", addr: 0x8000, want: 0x00000},
+		{name: "prg bank", writes: []registerWrite{{0x8000, 0x03}}, addr: 0x8123, want: 0x18123},
+		{name: "prg bank wraps", writes: []registerWrite{{0xffff, 0x05}}, addr: 0xffff, want: 0x0ffff},
+		{name: "nina registers ignored", writes: []registerWrite{{0x7ffd, 0x01}}, addr: 0x8000, want: 0x00000},
+		{name: "chr not banked", writes: []registerWrite{{0x7ffe, 0x01}}, ppu: true, addr: 0x1234, want: 0x01234},
+	})
+}
+
+func TestMapper034NINA(t *testing.T) {
+	//no submapper and more than 8 KB of CHR ROM is the NINA-001
+	create := func() Mapper {
+		return CreateMapper034(0, 4, 2, false)
+	}
+
+	runBankTests(t, create, []bankTest{
+		{name: "power on chr", ppu: true, addr: 0x1010, want: 0x01010},
+		{name: "prg bank", writes: []registerWrite{{0x7ffd, 0x01}}, addr: 0x8123, want: 0x08123},
+		{name: "prg bank one bit", writes: []registerWrite{{0x7ffd, 0x02}}, addr: 0x8000, want: 0x00000},
+		{name: "chr bank 0", writes: []registerWrite{{0x7ffe, 0x03}}, ppu: true, addr: 0x0010, want: 0x03010},
+		{name: "chr bank 1", writes: []registerWrite{{0x7fff, 0x02}}, ppu: true, addr: 0x1010, want: 0x02010},
+		{name: "rom writes ignored", writes: []registerWrite{{0x8000, 0x01}}, addr: 0x8000, want: 0x00000},
+	})
+}
+
+func TestMapper034Submapper(t *testing.T) {
+	//the submapper picks the board even when the CHR size says otherwise
+	runBankTests(t, func() Mapper { return CreateMapper034(1, 4, 1, false) }, []bankTest{
+		{name: "submapper 1 is nina", writes: []registerWrite{{0x7ffe, 0x01}}, ppu: true, addr: 0x0000, want: 0x01000},
+	})
+	runBankTests(t, func() Mapper { return CreateMapper034(2, 4, 2, false) }, []bankTest{
+		{name: "submapper 2 is bnrom", writes: []registerWrite{{0x8000, 0x01}}, addr: 0x8000, want: 0x08000},
+		{name: "submapper 2 chr not banked", writes: []registerWrite{{0x7ffe, 0x01}}, ppu: true, addr: 0x0000, want: 0x00000},
+	})
+}
+
+func TestMapper034BusConflicts(t *testing.T) {
+	tests := []struct {
+		name   string
+		mapper *Mapper034
+		want   bool
+	}{
+		{"bnrom", CreateMapper034(2, 4, 2, false), true},
+		{"nina", CreateMapper034(1, 4, 1, false), false},
+	}
+
+	for _, test := range tests {
+		if got := test.mapper.BusConflicts(); got != test.want {
+			t.Errorf("%s: bus conflicts %v, want %v", test.name, got, test.want)
+		}
+	}
+}
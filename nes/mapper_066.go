@@ -0,0 +1,64 @@
+package nes
+
+//mapper for NES-GNROM, NES-MHROM
+
+type Mapper066 struct {
+	//how many banks of memory for each type of data, CHR counts CHR-RAM
+	PRGBanks uint16
+	CHRBanks uint16
+	//if the board has CHR-RAM instead of CHR ROM
+	CHRRAM bool
+
+	//--PP --CC, which 32 KB PRG bank is at 0x8000, which 8 KB CHR bank is used
+	bankSelect uint8
+
+	//if writes to the ROM range are ANDed with the ROM's data
+	busConflicts bool
+}
+
+// accesses the CPU memory
+// in mapper 66 the whole 0x8000 - 0xffff range is one switchable 32 KB bank
+func (mapper *Mapper066) CPUMapRead(addr uint16) (uint32, bool) {
+	if addr >= 0x8000 && addr <= 0xffff {
+		//the bank count is in 16 KB banks, so halve it for 32 KB banks, a 16 KB ROM is just mirrored
+		banks := uint32(mapper.PRGBanks) / 2
+		if banks == 0 {
+			return uint32(addr & 0x3fff), true
+		}
+		return (uint32(mapper.bankSelect>>4&0x03)%banks)*0x8000 | uint32(addr&0x7fff), true
+	}
+
+	return 0x0000, false
+}
+
+// writing anywhere in 0x8000 - 0xffff selects both banks
+func (mapper *Mapper066) CPUMapWrite(addr uint16, data uint8) (uint32, bool) {
+	if addr >= 0x8000 && addr <= 0xffff {
+		mapAddr, _ := mapper.CPUMapRead(addr)
+		mapper.bankSelect = data
+		return mapAddr, true
+	}
+
+	return 0x0000, false
+}
+
+// accesses the PPU memory, all 8 KB is switched at once
+func (mapper *Mapper066) PPUMapRead(addr uint16) (uint32, bool) {
+	if addr >= 0x0000 && addr <= 0x1fff {
+		return (uint32(mapper.bankSelect&0x03)%uint32(mapper.CHRBanks))*0x2000 | uint32(addr), true
+	}
+
+	return 0x0000, false
+}
+
+func (mapper *Mapper066) PPUMapWrite(addr uint16) (uint32, bool) {
+	if addr >= 0x0000 && addr <= 0x1fff && mapper.CHRRAM {
+		return (uint32(mapper.bankSelect&0x03)%uint32(mapper.CHRBanks))*0x2000 | uint32(addr), true
+	}
+
+	return 0x0000, false
+}
+
+func (mapper *Mapper066) BusConflicts() bool {
+	return mapper.busConflicts
+}
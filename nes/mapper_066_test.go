@@ -0,0 +1,18 @@
+package nes
+
+import "testing"
+
+func TestMapper066Banks(t *testing.T) {
+	create := func() Mapper {
+		return &Mapper066{PRGBanks: 8, CHRBanks: 4}
+	}
+
+	runBankTests(t, create, []bankTest{
+		{name: "power on prg", addr: 0x8000, want: 0x00000},
+		{name: "power on chr", ppu: true, addr: 0x1234, want: 0x01234},
+		{name: "prg bank", writes: []registerWrite{{0x8000, 0x30}}, addr: 0x8123, want: 0x18123},
+		{name: "chr bank", writes: []registerWrite{{0xffff, 0x02}}, ppu: true, addr: 0x0400, want: 0x04400},
+		{name: "both banks", writes: []registerWrite{{0x8000, 0x13}}, addr: 0xffff, want: 0x0ffff},
+		{name: "unused bits ignored", writes: []registerWrite{{0x8000, 0xcc}}, ppu: true, addr: 0x0000, want: 0x00000},
+	})
+}
@@ -0,0 +1,171 @@
+package nes
+
+//mapper for the Sunsoft FME-7, and the Sunsoft 5A and 5B, which are the same with the 5B adding sound
+
+type Mapper069 struct {
+	//how many banks of memory for each type of data, CHR counts CHR-RAM
+	PRGBanks uint16
+	CHRBanks uint16
+	//if the board has CHR-RAM instead of CHR ROM
+	CHRRAM bool
+
+	//which of the 16 internal registers the next parameter write goes to
+	command uint8
+
+	//ER-B BBBB, the bank at 0x6000, RAM enable, RAM instead of ROM, which 8 KB bank
+	prgBank6000 uint8
+	//the 3 switchable 8 KB PRG banks at 0x8000, 0xa000 and 0xc000
+	prgBanks [3]uint8
+	//the 8 1 KB CHR banks
+	chrBanks [8]uint8
+	mirror   Mirror
+
+	//16 bit counter that counts down every CPU cycle, firing when it goes past 0
+	irqCounter   uint16
+	irqEnable    bool
+	irqCounterOn bool
+	irqActive    bool
+
+	audio sunsoft5BAudio
+}
+
+// accesses the CPU memory
+// in mapper 69 there are 4 8 KB banks, the first 3 are switchable and the last is fixed to the last bank
+// 0x6000 - 0x7fff is a fifth bank that can be ROM or RAM
+func (mapper *Mapper069) CPUMapRead(addr uint16) (uint32, bool) {
+	banks := uint32(mapper.PRGBanks) * 2
+
+	if addr >= 0x6000 && addr <= 0x7fff {
+		//the RAM is left to the cartridge
+		if mapper.prgBank6000&0x40 == 0x40 {
+			return 0x0000, false
+		}
+		return (uint32(mapper.prgBank6000&0x3f)%banks)*0x2000 | uint32(addr&0x1fff), true
+	}
+
+	if addr >= 0x8000 && addr <= 0xffff {
+		bank := banks - 1
+		if slot := (addr - 0x8000) / 0x2000; slot < 3 {
+			bank = uint32(mapper.prgBanks[slot])
+		}
+
+		return (bank%banks)*0x2000 | uint32(addr&0x1fff), true
+	}
+
+	return 0x0000, false
+}
+
+// 0x8000 picks the command, 0xa000 is its parameter, and on the 5B 0xc000 and 0xe000 are the sound's register select and data
+func (mapper *Mapper069) CPUMapWrite(addr uint16, data uint8) (uint32, bool) {
+	if addr >= 0x6000 && addr <= 0x7fff {
+		return mapper.CPUMapRead(addr)
+	}
+	if addr < 0x8000 {
+		return 0x0000, false
+	}
+
+	mapAddr, _ := mapper.CPUMapRead(addr)
+
+	switch addr & 0xe000 {
+	case 0x8000:
+		mapper.command = data & 0x0f
+	case 0xa000:
+		mapper.writeParameter(data)
+	case 0xc000:
+		mapper.audio.writeAddress(data)
+	case 0xe000:
+		mapper.audio.writeData(data)
+	}
+
+	return mapAddr, true
+}
+
+func (mapper *Mapper069) writeParameter(data uint8) {
+	switch command := mapper.command; {
+	case command <= 0x7:
+		mapper.chrBanks[command] = data
+	case command == 0x8:
+		mapper.prgBank6000 = data
+	case command <= 0xb:
+		mapper.prgBanks[command-0x9] = data & 0x3f
+	case command == 0xc:
+		switch data & 0x03 {
+		case 0:
+			mapper.mirror = VERTICAL
+		case 1:
+			mapper.mirror = HORIZONTAL
+		case 2:
+			mapper.mirror = ONESCREEN_LO
+		case 3:
+			mapper.mirror = ONESCREEN_HI
+		}
+	case command == 0xd:
+		//C--- ---T, counter enable, IRQ enable, writing it also acknowledges the IRQ
+		mapper.irqEnable = data&0x01 == 0x01
+		mapper.irqCounterOn = data&0x80 == 0x80
+		mapper.irqActive = false
+	case command == 0xe:
+		mapper.irqCounter = (mapper.irqCounter & 0xff00) | uint16(data)
+	case command == 0xf:
+		mapper.irqCounter = (mapper.irqCounter & 0x00ff) | uint16(data)<<8
+	}
+}
+
+// accesses the PPU memory, 8 1 KB banks
+func (mapper *Mapper069) PPUMapRead(addr uint16) (uint32, bool) {
+	if addr >= 0x0000 && addr <= 0x1fff {
+		return mapper.chrAddress(addr), true
+	}
+
+	return 0x0000, false
+}
+
+func (mapper *Mapper069) PPUMapWrite(addr uint16) (uint32, bool) {
+	if addr >= 0x0000 && addr <= 0x1fff && mapper.CHRRAM {
+		return mapper.chrAddress(addr), true
+	}
+
+	return 0x0000, false
+}
+
+func (mapper *Mapper069) chrAddress(addr uint16) uint32 {
+	//there are 8 1 KB banks in each 8 KB bank
+	banks := uint32(mapper.CHRBanks) * 8
+	if banks == 0 {
+		banks = 8
+	}
+
+	return (uint32(mapper.chrBanks[addr/0x0400])%banks)*0x0400 | uint32(addr&0x03ff)
+}
+
+func (mapper *Mapper069) Mirror() Mirror {
+	return mapper.mirror
+}
+
+// the RAM bank only works when it's enabled
+func (mapper *Mapper069) PRGRAMMapRead(addr uint16) (uint32, bool) {
+	return uint32(mapper.prgBank6000&0x3f)*0x2000 | uint32(addr&0x1fff), mapper.prgBank6000&0xc0 == 0xc0
+}
+
+func (mapper *Mapper069) PRGRAMMapWrite(addr uint16) (uint32, bool) {
+	return uint32(mapper.prgBank6000&0x3f)*0x2000 | uint32(addr&0x1fff), mapper.prgBank6000&0xc0 == 0xc0
+}
+
+func (mapper *Mapper069) IRQ() bool {
+	return mapper.irqActive
+}
+
+func (mapper *Mapper069) CPUClock() {
+	if mapper.irqCounterOn {
+		mapper.irqCounter--
+		if mapper.irqCounter == 0xffff && mapper.irqEnable {
+			mapper.irqActive = true
+		}
+	}
+
+	mapper.audio.clock()
+}
+
+func (mapper *Mapper069) AudioSample() float32 {
+	return mapper.audio.sample()
+}
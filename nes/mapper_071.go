@@ -0,0 +1,85 @@
+package nes
+
+//mapper for the Camerica and Codemasters boards, BF909x
+//it's UxROM with the register moved to 0xc000, the Fire Hawk board (submapper 1) also has one-screen mirroring at 0x9000
+
+type Mapper071 struct {
+	//how many banks of memory for each type of data
+	PRGBanks uint16
+	CHRBanks uint16
+	//if the board has CHR-RAM instead of CHR ROM, all of them do
+	CHRRAM bool
+
+	//if the board has the mirroring register
+	mirrorControl bool
+
+	//which 16 KB bank is at 0x8000
+	prgBank uint8
+	mirror  Mirror
+}
+
+func CreateMapper071(submapperID uint8, prgBanks uint16, chrBanks uint16, chrRAM bool, mirror Mirror) *Mapper071 {
+	return &Mapper071{
+		PRGBanks:      prgBanks,
+		CHRBanks:      chrBanks,
+		CHRRAM:        chrRAM,
+		mirrorControl: submapperID == 1,
+		mirror:        mirror,
+	}
+}
+
+// accesses the CPU memory
+// in mapper 71, 0x8000 - 0xbfff is a switchable 16 KB bank and 0xc000 - 0xffff is fixed to the last bank
+func (mapper *Mapper071) CPUMapRead(addr uint16) (uint32, bool) {
+	if addr >= 0x8000 && addr <= 0xbfff {
+		return (uint32(mapper.prgBank)%uint32(mapper.PRGBanks))*0x4000 | uint32(addr&0x3fff), true
+	}
+	if addr >= 0xc000 && addr <= 0xffff {
+		return uint32(mapper.PRGBanks-1)*0x4000 | uint32(addr&0x3fff), true
+	}
+
+	return 0x0000, false
+}
+
+// writing in 0xc000 - 0xffff selects the bank at 0x8000, and on Fire Hawk 0x9000 - 0x9fff picks the one-screen nametable
+func (mapper *Mapper071) CPUMapWrite(addr uint16, data uint8) (uint32, bool) {
+	if addr < 0x8000 {
+		return 0x0000, false
+	}
+
+	mapAddr, _ := mapper.CPUMapRead(addr)
+
+	switch {
+	case addr >= 0xc000:
+		mapper.prgBank = data
+	case addr >= 0x9000 && addr <= 0x9fff && mapper.mirrorControl:
+		if data&0x10 == 0x10 {
+			mapper.mirror = ONESCREEN_HI
+		} else {
+			mapper.mirror = ONESCREEN_LO
+		}
+	}
+
+	return mapAddr, true
+}
+
+// the CHR isn't banked, so it's mapped directly
+func (mapper *Mapper071) PPUMapRead(addr uint16) (uint32, bool) {
+	if addr >= 0x0000 && addr <= 0x1fff {
+		return uint32(addr), true
+	}
+
+	return 0x0000, false
+}
+
+func (mapper *Mapper071) PPUMapWrite(addr uint16) (uint32, bool) {
+	if addr >= 0x0000 && addr <= 0x1fff && mapper.CHRRAM {
+		return uint32(addr), true
+	}
+
+	return 0x0000, false
+}
+
+func (mapper *Mapper071) Mirror() Mirror {
+	return mapper.mirror
+}
@@ -0,0 +1,45 @@
+package nes
+
+import "testing"
+
+func TestMapper071Banks(t *testing.T) {
+	create := func() Mapper {
+		return CreateMapper071(0, 8, 1, true, VERTICAL)
+	}
+
+	runBankTests(t, create, []bankTest{
+		{name: "power on", addr: 0x8000, want: 0x00000},
+		{name: "last bank fixed", writes: []registerWrite{{0xc000, 0x02}}, addr: 0xc123, want: 0x1c123},
+		{name: "prg bank", writes: []registerWrite{{0xc000, 0x05}}, addr: 0x8123, want: 0x14123},
+		{name: "prg bank top register", writes: []registerWrite{{0xffff, 0x03}}, addr: 0xbfff, want: 0x0ffff},
+		{name: "prg bank wraps", writes: []registerWrite{{0xc000, 0x09}}, addr: 0x8000, want: 0x04000},
+		{name: "low writes ignored", writes: []registerWrite{{0x8000, 0x05}}, addr: 0x8000, want: 0x00000},
+		{name: "mirroring write keeps bank", writes: []registerWrite{{0xc000, 0x01}, {0x9000, 0x10}}, addr: 0x8000, want: 0x04000},
+	})
+}
+
+func TestMapper071Mirroring(t *testing.T) {
+	tests := []struct {
+		name      string
+		submapper uint8
+		writes    []registerWrite
+		want      Mirror
+	}{
+		{"header mirroring", 0, nil, VERTICAL},
+		{"no register without submapper", 0, []registerWrite{{0x9000, 0x10}}, VERTICAL},
+		{"fire hawk high", 1, []registerWrite{{0x9000, 0x10}}, ONESCREEN_HI},
+		{"fire hawk low", 1, []registerWrite{{0x9000, 0x10}, {0x9fff, 0x00}}, ONESCREEN_LO},
+		{"fire hawk outside register", 1, []registerWrite{{0x8000, 0x10}, {0xa000, 0x10}}, VERTICAL},
+	}
+
+	for _, test := range tests {
+		mapper := CreateMapper071(test.submapper, 8, 1, true, VERTICAL)
+		for _, write := range test.writes {
+			mapper.CPUMapWrite(write.addr, write.data)
+		}
+
+		if got := mapper.Mirror(); got != test.want {
+			t.Errorf("%s: mirroring %v, want %v", test.name, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package nes
+
+//mapper for the AVE NINA-03 and NINA-06, also used by a lot of Sachen and HES games
+
+type Mapper079 struct {
+	//how many banks of memory for each type of data, CHR counts CHR-RAM
+	PRGBanks uint16
+	CHRBanks uint16
+	//if the board has CHR-RAM instead of CHR ROM
+	CHRRAM bool
+
+	//---- PCCC, which 32 KB PRG bank is at 0x8000, which 8 KB CHR bank is used
+	bankSelect uint8
+}
+
+// accesses the CPU memory
+// in mapper 79 the whole 0x8000 - 0xffff range is one switchable 32 KB bank
+func (mapper *Mapper079) CPUMapRead(addr uint16) (uint32, bool) {
+	if addr >= 0x8000 && addr <= 0xffff {
+		//the bank count is in 16 KB banks, so halve it for 32 KB banks, a 16 KB ROM is just mirrored
+		banks := uint32(mapper.PRGBanks) / 2
+		if banks == 0 {
+			return uint32(addr & 0x3fff), true
+		}
+		return (uint32(mapper.bankSelect>>3&0x01)%banks)*0x8000 | uint32(addr&0x7fff), true
+	}
+
+	return 0x0000, false
+}
+
+// the register is in 0x4100 - 0x5fff, on every address with A8 set, and the ROM can't be written
+func (mapper *Mapper079) CPUMapWrite(addr uint16, data uint8) (uint32, bool) {
+	if addr >= 0x4100 && addr <= 0x5fff && addr&0x0100 == 0x0100 {
+		mapper.bankSelect = data
+		return 0x0000, true
+	}
+	if addr >= 0x8000 && addr <= 0xffff {
+		return mapper.CPUMapRead(addr)
+	}
+
+	return 0x0000, false
+}
+
+// accesses the PPU memory, all 8 KB is switched at once
+func (mapper *Mapper079) PPUMapRead(addr uint16) (uint32, bool) {
+	if addr >= 0x0000 && addr <= 0x1fff {
+		return (uint32(mapper.bankSelect&0x07)%uint32(mapper.CHRBanks))*0x2000 | uint32(addr), true
+	}
+
+	return 0x0000, false
+}
+
+func (mapper *Mapper079) PPUMapWrite(addr uint16) (uint32, bool) {
+	if addr >= 0x0000 && addr <= 0x1fff && mapper.CHRRAM {
+		return (uint32(mapper.bankSelect&0x07)%uint32(mapper.CHRBanks))*0x2000 | uint32(addr), true
+	}
+
+	return 0x0000, false
+}
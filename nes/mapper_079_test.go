@@ -0,0 +1,23 @@
+package nes
+
+import "testing"
+
+func TestMapper079Banks(t *testing.T) {
+	create := func() Mapper {
+		return &Mapper079{PRGBanks: 4, CHRBanks: 8}
+	}
+
+	runBankTests(t, create, []bankTest{
+		{name: "power on", ppu: true, addr: 0x1234, want: 0x01234},
+		{name: "prg bank", writes: []registerWrite{{0x4100, 0x08}}, addr: 0x8123, want: 0x08123},
+		{name: "chr bank", writes: []registerWrite{{0x4100, 0x05}}, ppu: true, addr: 0x0123, want: 0x0a123},
+		{name: "both banks", writes: []registerWrite{{0x4100, 0x0d}}, addr: 0xffff, want: 0x0ffff},
+		//the register is on every address in 0x4100 - 0x5fff with A8 set
+		{name: "a8 set high", writes: []registerWrite{{0x5f00, 0x03}}, ppu: true, addr: 0x0000, want: 0x06000},
+		{name: "a8 set low bits", writes: []registerWrite{{0x43ff, 0x02}}, ppu: true, addr: 0x0000, want: 0x04000},
+		{name: "a8 clear", writes: []registerWrite{{0x4200, 0x03}}, ppu: true, addr: 0x0000, want: 0x00000},
+		{name: "below register", writes: []registerWrite{{0x4000, 0x03}}, ppu: true, addr: 0x0000, want: 0x00000},
+		{name: "above register", writes: []registerWrite{{0x6100, 0x03}}, ppu: true, addr: 0x0000, want: 0x00000},
+		{name: "rom writes ignored", writes: []registerWrite{{0x8100, 0x0f}}, addr: 0x8000, want: 0x00000},
+	})
+}
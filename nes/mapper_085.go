@@ -0,0 +1,176 @@
+package nes
+
+//mapper for the Konami VRC7
+//the VRC7a uses A4 as the second register of each pair and the VRC7b uses A3, when the submapper isn't known either works
+
+type Mapper085 struct {
+	//how many banks of memory for each type of data, CHR counts CHR-RAM
+	PRGBanks uint16
+	CHRBanks uint16
+	//if the board has CHR-RAM instead of CHR ROM
+	CHRRAM bool
+
+	//the CPU address line that picks the second register of each pair
+	pairLines uint16
+
+	//the 3 switchable 8 KB PRG banks
+	prgBanks [3]uint8
+	//the 8 1 KB CHR banks
+	chrBanks [8]uint8
+	//WS-- --MM, PRG-RAM enable, sound reset, mirroring
+	control uint8
+
+	irq   vrcIRQ
+	audio opll
+}
+
+func CreateMapper085(submapperID uint8, prgBanks uint16, chrBanks uint16, chrRAM bool) *Mapper085 {
+	mapper := Mapper085{
+		PRGBanks: prgBanks,
+		CHRBanks: chrBanks,
+		CHRRAM:   chrRAM,
+	}
+	switch submapperID {
+	case 1: //VRC7b
+		mapper.pairLines = 0x08
+	case 2: //VRC7a
+		mapper.pairLines = 0x10
+	default:
+		mapper.pairLines = 0x18
+	}
+
+	return &mapper
+}
+
+// accesses the CPU memory
+// in mapper 85 there are 4 8 KB banks, the first 3 are switchable and the last is fixed to the last bank
+func (mapper *Mapper085) CPUMapRead(addr uint16) (uint32, bool) {
+	if addr >= 0x8000 && addr <= 0xffff {
+		banks := uint32(mapper.PRGBanks) * 2
+
+		bank := banks - 1
+		if slot := (addr - 0x8000) / 0x2000; slot < 3 {
+			bank = uint32(mapper.prgBanks[slot])
+		}
+
+		return (bank%banks)*0x2000 | uint32(addr&0x1fff), true
+	}
+
+	return 0x0000, false
+}
+
+// the registers are in pairs every 0x1000 bytes
+func (mapper *Mapper085) CPUMapWrite(addr uint16, data uint8) (uint32, bool) {
+	if addr < 0x8000 {
+		return 0x0000, false
+	}
+
+	mapAddr, _ := mapper.CPUMapRead(addr)
+	second := addr&mapper.pairLines != 0
+
+	switch addr & 0xf000 {
+	case 0x8000:
+		if second {
+			mapper.prgBanks[1] = data & 0x3f
+		} else {
+			mapper.prgBanks[0] = data & 0x3f
+		}
+	case 0x9000:
+		//0x9010 picks the sound register and 0x9030 writes it, 0x9000 is the third PRG bank
+		switch {
+		case addr&0x0030 == 0x0030:
+			mapper.audio.writeData(data)
+		case second:
+			mapper.audio.writeAddress(data)
+		default:
+			mapper.prgBanks[2] = data & 0x3f
+		}
+	case 0xa000, 0xb000, 0xc000, 0xd000:
+		bank := (addr - 0xa000) / 0x1000 * 2
+		if second {
+			bank++
+		}
+		mapper.chrBanks[bank] = data
+	case 0xe000:
+		if second {
+			mapper.irq.latch = data
+		} else {
+			//the sound reset holds the chip silent until it's cleared
+			if data&0x40 == 0x40 {
+				mapper.audio.reset()
+			}
+			mapper.control = data
+		}
+	case 0xf000:
+		if second {
+			mapper.irq.acknowledge()
+		} else {
+			mapper.irq.writeControl(data)
+		}
+	}
+
+	return mapAddr, true
+}
+
+// accesses the PPU memory, 8 1 KB banks
+func (mapper *Mapper085) PPUMapRead(addr uint16) (uint32, bool) {
+	if addr >= 0x0000 && addr <= 0x1fff {
+		return mapper.chrAddress(addr), true
+	}
+
+	return 0x0000, false
+}
+
+func (mapper *Mapper085) PPUMapWrite(addr uint16) (uint32, bool) {
+	if addr >= 0x0000 && addr <= 0x1fff && mapper.CHRRAM {
+		return mapper.chrAddress(addr), true
+	}
+
+	return 0x0000, false
+}
+
+func (mapper *Mapper085) chrAddress(addr uint16) uint32 {
+	//there are 8 1 KB banks in each 8 KB bank
+	banks := uint32(mapper.CHRBanks) * 8
+	if banks == 0 {
+		banks = 8
+	}
+
+	return (uint32(mapper.chrBanks[addr/0x0400])%banks)*0x0400 | uint32(addr&0x03ff)
+}
+
+func (mapper *Mapper085) Mirror() Mirror {
+	switch mapper.control & 0x03 {
+	case 0:
+		return VERTICAL
+	case 1:
+		return HORIZONTAL
+	case 2:
+		return ONESCREEN_LO
+	}
+	return ONESCREEN_HI
+}
+
+// the PRG-RAM is only there when it's enabled
+func (mapper *Mapper085) PRGRAMMapRead(addr uint16) (uint32, bool) {
+	return uint32(addr - 0x6000), mapper.control&0x80 == 0x80
+}
+
+func (mapper *Mapper085) PRGRAMMapWrite(addr uint16) (uint32, bool) {
+	return uint32(addr - 0x6000), mapper.control&0x80 == 0x80
+}
+
+func (mapper *Mapper085) IRQ() bool {
+	return mapper.irq.active
+}
+
+func (mapper *Mapper085) CPUClock() {
+	mapper.irq.clock()
+	if mapper.control&0x40 == 0 {
+		mapper.audio.clock()
+	}
+}
+
+func (mapper *Mapper085) AudioSample() float32 {
+	return mapper.audio.sample()
+}
@@ -0,0 +1,97 @@
+package nes
+
+//mapper for the Namco 108 and the boards that use it, DxROM, Namco 118 and Tengen MIMIC-1
+//it's the chip the MMC3 was based on, with the same bank registers but without the bank modes, mirroring control, PRG-RAM or IRQ
+
+type Mapper206 struct {
+	//how many banks of memory for each type of data, CHR counts CHR-RAM
+	PRGBanks uint16
+	CHRBanks uint16
+	//if the board has CHR-RAM instead of CHR ROM
+	CHRRAM bool
+
+	//which bank register the next data write goes to
+	bankSelect uint8
+	//R0 - R7, R0 and R1 are 2 KB CHR banks at 0x0000, R2 - R5 are 1 KB CHR banks at 0x1000, R6 and R7 are 8 KB PRG banks at 0x8000 and 0xa000
+	registers [8]uint8
+}
+
+// accesses the CPU memory
+// in mapper 206 the PRG is 4 8 KB banks, R6, R7, then the last 2 banks
+func (mapper *Mapper206) CPUMapRead(addr uint16) (uint32, bool) {
+	if addr >= 0x8000 && addr <= 0xffff {
+		banks := uint32(mapper.PRGBanks) * 2
+
+		var bank uint32
+		switch (addr - 0x8000) / 0x2000 {
+		case 0:
+			bank = uint32(mapper.registers[6])
+		case 1:
+			bank = uint32(mapper.registers[7])
+		case 2:
+			bank = banks - 2
+		case 3:
+			bank = banks - 1
+		}
+
+		return (bank%banks)*0x2000 | uint32(addr&0x1fff), true
+	}
+
+	return 0x0000, false
+}
+
+// the registers are only in 0x8000 - 0x9fff, even addresses select the bank register, odd addresses write it
+func (mapper *Mapper206) CPUMapWrite(addr uint16, data uint8) (uint32, bool) {
+	if addr < 0x8000 {
+		return 0x0000, false
+	}
+
+	mapAddr, _ := mapper.CPUMapRead(addr)
+
+	if addr <= 0x9fff {
+		if addr&1 == 0 {
+			mapper.bankSelect = data & 0x07
+		} else {
+			//the chip only has 6 CHR and 4 PRG address lines
+			mask := uint8(0x3f)
+			if mapper.bankSelect >= 6 {
+				mask = 0x0f
+			}
+			mapper.registers[mapper.bankSelect] = data & mask
+		}
+	}
+
+	return mapAddr, true
+}
+
+// accesses the PPU memory, 2 2 KB banks then 4 1 KB banks
+func (mapper *Mapper206) PPUMapRead(addr uint16) (uint32, bool) {
+	if addr >= 0x0000 && addr <= 0x1fff {
+		return mapper.chrAddress(addr), true
+	}
+
+	return 0x0000, false
+}
+
+func (mapper *Mapper206) PPUMapWrite(addr uint16) (uint32, bool) {
+	if addr >= 0x0000 && addr <= 0x1fff && mapper.CHRRAM {
+		return mapper.chrAddress(addr), true
+	}
+
+	return 0x0000, false
+}
+
+func (mapper *Mapper206) chrAddress(addr uint16) uint32 {
+	//there are 8 1 KB banks in each 8 KB bank
+	banks := uint32(mapper.CHRBanks) * 8
+
+	var bank uint32
+	if addr < 0x1000 {
+		//the 2 KB banks ignore the low bit
+		bank = uint32(mapper.registers[addr/0x0800]&0xfe) | uint32(addr/0x0400&1)
+	} else {
+		bank = uint32(mapper.registers[2+(addr-0x1000)/0x0400])
+	}
+
+	return (bank%banks)*0x0400 | uint32(addr&0x03ff)
+}
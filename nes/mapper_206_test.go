@@ -0,0 +1,25 @@
+package nes
+
+import "testing"
+
+func TestMapper206Banks(t *testing.T) {
+	create := func() Mapper {
+		return &Mapper206{PRGBanks: 8, CHRBanks: 8}
+	}
+
+	runBankTests(t, create, []bankTest{
+		{name: "power on 0x8000", addr: 0x8000, want: 0x00000},
+		{name: "second last fixed", addr: 0xc123, want: 0x1c123},
+		{name: "last fixed", addr: 0xffff, want: 0x1ffff},
+		{name: "r6", writes: []registerWrite{{0x8000, 6}, {0x8001, 5}}, addr: 0x8123, want: 0x0a123},
+		{name: "r7 masked", writes: []registerWrite{{0x8000, 7}, {0x8001, 0x1f}}, addr: 0xa000, want: 0x1e000},
+		{name: "r0 ignores low bit", writes: []registerWrite{{0x8000, 0}, {0x8001, 5}}, ppu: true, addr: 0x0000, want: 0x01000},
+		{name: "r0 second half", writes: []registerWrite{{0x8000, 0}, {0x8001, 5}}, ppu: true, addr: 0x0410, want: 0x01410},
+		{name: "r1", writes: []registerWrite{{0x8000, 1}, {0x8001, 8}}, ppu: true, addr: 0x0800, want: 0x02000},
+		{name: "r2", writes: []registerWrite{{0x8000, 2}, {0x8001, 9}}, ppu: true, addr: 0x1010, want: 0x02410},
+		{name: "r5", writes: []registerWrite{{0x8000, 5}, {0x8001, 0x3f}}, ppu: true, addr: 0x1fff, want: 0x0ffff},
+		{name: "registers mirrored", writes: []registerWrite{{0x9ffe, 6}, {0x9fff, 3}}, addr: 0x8000, want: 0x06000},
+		{name: "no registers above 0x9fff", writes: []registerWrite{{0xa000, 6}, {0xa001, 3}}, addr: 0x8000, want: 0x00000},
+		{name: "select bits masked", writes: []registerWrite{{0x8000, 0x0e}, {0x8001, 3}}, addr: 0x8000, want: 0x06000},
+	})
+}
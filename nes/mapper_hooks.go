@@ -0,0 +1,152 @@
+package nes
+
+// the optional interfaces the cartridge's mapper implements, found once when the mapper is made instead of on every access
+// a nil field means the board doesn't have that hardware, and the cartridge acts like a board without it
+type mapperHooks struct {
+	//mirroring
+	mirror         MirrorMapper
+	nameTablePages NameTablePageMapper
+	nameTable      NameTableMapper
+
+	//memory
+	prgRAM     PRGRAMMapper
+	prgRAMBank PRGRAMBankMapper
+	cpuData    CPUDataMapper
+	ppuData    PPUDataMapper
+	saveRAM    SaveRAMMapper
+
+	//timing
+	cpuClock   CPUClocker
+	ppuAddress PPUAddressWatcher
+	ppuFrame   PPUFrameWatcher
+	reset      ResetMapper
+
+	//output
+	irq   IRQMapper
+	audio AudioMapper
+
+	//writes
+	busConflicts BusConflictMapper
+}
+
+// checks which of the optional interfaces the mapper implements
+func findMapperHooks(mapper Mapper) mapperHooks {
+	hooks := mapperHooks{}
+
+	hooks.mirror, _ = mapper.(MirrorMapper)
+	hooks.nameTablePages, _ = mapper.(NameTablePageMapper)
+	hooks.nameTable, _ = mapper.(NameTableMapper)
+
+	hooks.prgRAM, _ = mapper.(PRGRAMMapper)
+	hooks.prgRAMBank, _ = mapper.(PRGRAMBankMapper)
+	hooks.cpuData, _ = mapper.(CPUDataMapper)
+	hooks.ppuData, _ = mapper.(PPUDataMapper)
+	hooks.saveRAM, _ = mapper.(SaveRAMMapper)
+
+	hooks.cpuClock, _ = mapper.(CPUClocker)
+	hooks.ppuAddress, _ = mapper.(PPUAddressWatcher)
+	hooks.ppuFrame, _ = mapper.(PPUFrameWatcher)
+	hooks.reset, _ = mapper.(ResetMapper)
+
+	hooks.irq, _ = mapper.(IRQMapper)
+	hooks.audio, _ = mapper.(AudioMapper)
+
+	hooks.busConflicts, _ = mapper.(BusConflictMapper)
+
+	return hooks
+}
+
+// gets the current nametable mirroring, which the mapper can change while the game runs
+func (cart *Cartridge) GetMirror() Mirror {
+	if cart.hooks.mirror != nil {
+		return cart.hooks.mirror.Mirror()
+	}
+
+	return cart.Mirror
+}
+
+// maps a CPU address to an offset in the PRG-RAM, asking the mapper if it controls the RAM, otherwise it's mirrored if it's smaller than 8 KB
+func (cart *Cartridge) prgRAMMapRead(addr uint16) (uint32, bool) {
+	if cart.hooks.prgRAM != nil {
+		ramAddr, enabled := cart.hooks.prgRAM.PRGRAMMapRead(addr)
+		return ramAddr % uint32(len(cart.PRGRAM)), enabled
+	}
+
+	return uint32(addr-0x6000) % uint32(len(cart.PRGRAM)), true
+}
+
+func (cart *Cartridge) prgRAMMapWrite(addr uint16) (uint32, bool) {
+	if cart.hooks.prgRAM != nil {
+		ramAddr, enabled := cart.hooks.prgRAM.PRGRAMMapWrite(addr)
+		return ramAddr % uint32(len(cart.PRGRAM)), enabled
+	}
+
+	return uint32(addr-0x6000) % uint32(len(cart.PRGRAM)), true
+}
+
+// gets the RAM inside the mapper that the battery keeps, nil if it doesn't have any
+func (cart *Cartridge) mapperSaveRAM() []uint8 {
+	if cart.hooks.saveRAM != nil {
+		return cart.hooks.saveRAM.SaveRAM()
+	}
+
+	return nil
+}
+
+// advances the cartridge one CPU cycle, for mappers that keep track of time
+func (cart *Cartridge) CPUClock() {
+	if cart.hooks.cpuClock != nil {
+		cart.hooks.cpuClock.CPUClock()
+	}
+}
+
+// lets the mapper see the address on the PPU bus
+// palettes are inside the PPU, so those addresses never reach the cartridge
+func (cart *Cartridge) watchPPUAddress(addr uint16) {
+	if addr >= 0x3f00 {
+		return
+	}
+	if cart.hooks.ppuAddress != nil {
+		cart.hooks.ppuAddress.PPUAddress(addr)
+	}
+}
+
+// tells the mapper the PPU started a new scanline, and if it's rendering
+func (cart *Cartridge) PPUScanline(line int, rendering bool) {
+	if cart.hooks.ppuFrame != nil {
+		cart.hooks.ppuFrame.PPUScanline(line, rendering)
+	}
+}
+
+// tells the mapper if the PPU is fetching sprite tiles or background tiles
+func (cart *Cartridge) PPUFetchingSprites(sprites bool) {
+	if cart.hooks.ppuFrame != nil {
+		cart.hooks.ppuFrame.PPUFetchingSprites(sprites)
+	}
+}
+
+// tells the mapper the console was reset, for the few that notice
+func (cart *Cartridge) Reset() {
+	if cart.hooks.reset != nil {
+		cart.hooks.reset.Reset()
+		cart.updateNameTablePages()
+	}
+}
+
+// checks if the cartridge is holding the CPU's IRQ line
+func (cart *Cartridge) IRQ() bool {
+	if cart.hooks.irq != nil {
+		return cart.hooks.irq.IRQ()
+	}
+
+	return false
+}
+
+// gets the output of the cartridge's own sound hardware, silent if it doesn't have any
+func (cart *Cartridge) AudioSample() float32 {
+	if cart.hooks.audio != nil {
+		return cart.hooks.audio.AudioSample()
+	}
+
+	return 0
+}
@@ -0,0 +1,44 @@
+package nes
+
+import "testing"
+
+// a write to one of a mapper's registers
+type registerWrite struct {
+	addr uint16
+	data uint8
+}
+
+// a bank switching test, the writes are made to a new mapper and then addr is mapped through its CPU or PPU side
+type bankTest struct {
+	name   string
+	writes []registerWrite
+	ppu    bool
+	addr   uint16
+	want   uint32
+}
+
+// runs each test on a fresh mapper from create, checking the address the mapper gives back
+func runBankTests(t *testing.T, create func() Mapper, tests []bankTest) {
+	t.Helper()
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mapper := create()
+			for _, write := range test.writes {
+				mapper.CPUMapWrite(write.addr, write.data)
+			}
+
+			var got uint32
+			var ok bool
+			if test.ppu {
+				got, ok = mapper.PPUMapRead(test.addr)
+			} else {
+				got, ok = mapper.CPUMapRead(test.addr)
+			}
+
+			if !ok || got != test.want {
+				t.Errorf("0x%04x mapped to 0x%05x (%v), want 0x%05x", test.addr, got, ok, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,332 @@
+package nes
+
+import "math"
+
+// an OPLL, the FM synthesizer the VRC7's sound is based on, a cut down YM2413 with 6 channels and its own instrument ROM
+// each channel is 2 operators, a modulator that changes the phase of the carrier, which is what's heard
+// the envelopes and levels are kept in decibels of attenuation like the real chip, but the math is done with floats instead of its log tables
+
+// the chip runs off the VRC7's 3.58 MHz clock and makes a sample every 72 clocks, which is every 36 CPU cycles
+const (
+	opllSampleRate = 3579545.0 / 72
+	opllCPUCycles  = 36
+)
+
+// the built in instruments 1 - 15, in the same layout as the custom instrument registers 0x00 - 0x07
+var vrc7Patches = [15][8]uint8{
+	{0x03, 0x21, 0x05, 0x06, 0xe8, 0x81, 0x42, 0x27},
+	{0x13, 0x41, 0x14, 0x0d, 0xd8, 0xf6, 0x23, 0x12},
+	{0x11, 0x11, 0x08, 0x08, 0xfa, 0xb2, 0x20, 0x12},
+	{0x31, 0x61, 0x0c, 0x07, 0xa8, 0x64, 0x61, 0x27},
+	{0x32, 0x21, 0x1e, 0x06, 0xe1, 0x76, 0x01, 0x28},
+	{0x02, 0x01, 0x06, 0x00, 0xa3, 0xe2, 0xf4, 0xf4},
+	{0x21, 0x61, 0x1d, 0x07, 0x82, 0x81, 0x11, 0x07},
+	{0x23, 0x21, 0x22, 0x17, 0xa2, 0x72, 0x01, 0x17},
+	{0x35, 0x11, 0x25, 0x00, 0x40, 0x73, 0x72, 0x01},
+	{0xb5, 0x01, 0x0f, 0x0f, 0xa8, 0xa5, 0x51, 0x02},
+	{0x17, 0xc1, 0x24, 0x07, 0xf8, 0xf8, 0x22, 0x12},
+	{0x71, 0x23, 0x11, 0x06, 0x65, 0x74, 0x18, 0x16},
+	{0x01, 0x02, 0xd3, 0x05, 0xc9, 0x95, 0x03, 0x02},
+	{0x61, 0x63, 0x0c, 0x00, 0x94, 0xc0, 0x33, 0xf6},
+	{0x21, 0x72, 0x0d, 0x00, 0xc1, 0xd5, 0x56, 0x06},
+}
+
+// the frequency multiplier for each value of the MULT bits
+var opllMultipliers = [16]float64{0.5, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 10, 12, 12, 15, 15}
+
+// the key scale level attenuation in decibels for the top 4 bits of the F-number, at the highest block
+var opllKeyScaleLevels = [16]float64{0, 18, 24, 27.75, 30, 32.25, 33.75, 35.25, 36, 37.5, 38.25, 39, 39.75, 40.5, 41.25, 42}
+
+// the most an envelope can attenuate an operator, past this it's silent
+const opllMaxAttenuation = 48.0
+
+type opllEnvelopeState uint8
+
+const (
+	opllOff opllEnvelopeState = iota
+	opllAttack
+	opllDecay
+	opllSustain
+	opllRelease
+)
+
+type opllOperator struct {
+	//where in the sine wave the operator is, from 0 to 1
+	phase float64
+
+	state opllEnvelopeState
+	//the envelope's attenuation in decibels
+	envelope float64
+
+	//the last 2 outputs, averaged for the modulator's feedback
+	outputs [2]float64
+}
+
+type opllChannel struct {
+	//9 bit F-number and 3 bit block, the block is the octave
+	fnum  uint16
+	block uint8
+
+	keyOn   bool
+	sustain bool
+	//which instrument is used, 0 is the custom instrument, and the carrier's volume in 3 dB steps
+	instrument uint8
+	volume     uint8
+
+	//the modulator then the carrier
+	operators [2]opllOperator
+}
+
+type opll struct {
+	//the register the next data write goes to
+	address uint8
+	//registers 0x00 - 0x07, the custom instrument
+	custom   [8]uint8
+	channels [6]opllChannel
+
+	//the phases of the tremolo and vibrato, from 0 to 1
+	tremolo float64
+	vibrato float64
+
+	//CPU cycles since the last sample, and the last sample made
+	cycles int
+	output float32
+}
+
+// puts the chip back in its power on state, which silences it
+func (chip *opll) reset() {
+	*chip = opll{}
+}
+
+func (chip *opll) writeAddress(data uint8) {
+	chip.address = data
+}
+
+// writes the data to the register that was picked with writeAddress
+func (chip *opll) writeData(data uint8) {
+	addr := chip.address
+	switch {
+	case addr <= 0x07:
+		chip.custom[addr] = data
+	case addr >= 0x10 && addr <= 0x15:
+		channel := &chip.channels[addr-0x10]
+		channel.fnum = (channel.fnum & 0x100) | uint16(data)
+	case addr >= 0x20 && addr <= 0x25:
+		channel := &chip.channels[addr-0x20]
+		channel.fnum = (channel.fnum & 0x0ff) | uint16(data&0x01)<<8
+		channel.block = (data >> 1) & 0x07
+		channel.sustain = data&0x20 == 0x20
+		keyOn := data&0x10 == 0x10
+		if keyOn && !channel.keyOn {
+			channel.startNote()
+		} else if !keyOn && channel.keyOn {
+			channel.operators[0].releaseNote()
+			channel.operators[1].releaseNote()
+		}
+		channel.keyOn = keyOn
+	case addr >= 0x30 && addr <= 0x35:
+		channel := &chip.channels[addr-0x30]
+		channel.instrument = data >> 4
+		channel.volume = data & 0x0f
+	}
+}
+
+// a new note restarts the waveforms and the envelopes, a silent operator starts its attack from the bottom
+func (channel *opllChannel) startNote() {
+	for i := range channel.operators {
+		operator := &channel.operators[i]
+		operator.phase = 0
+		if operator.state == opllOff {
+			operator.envelope = opllMaxAttenuation
+		}
+		operator.state = opllAttack
+	}
+}
+
+func (operator *opllOperator) releaseNote() {
+	if operator.state != opllOff {
+		operator.state = opllRelease
+	}
+}
+
+// the 8 bytes describing the channel's instrument
+func (chip *opll) patch(channel *opllChannel) [8]uint8 {
+	if channel.instrument == 0 {
+		return chip.custom
+	}
+	return vrc7Patches[channel.instrument-1]
+}
+
+// runs the chip for one CPU cycle, making a new sample every 36 cycles
+func (chip *opll) clock() {
+	chip.cycles++
+	if chip.cycles < opllCPUCycles {
+		return
+	}
+	chip.cycles = 0
+
+	//the tremolo is about 3.7 Hz and the vibrato about 6.4 Hz
+	chip.tremolo = math.Mod(chip.tremolo+3.7/opllSampleRate, 1)
+	chip.vibrato = math.Mod(chip.vibrato+6.4/opllSampleRate, 1)
+
+	total := 0.0
+	for i := range chip.channels {
+		total += chip.channelOutput(&chip.channels[i])
+	}
+	chip.output = float32(total / 6)
+}
+
+func (chip *opll) sample() float32 {
+	return chip.output
+}
+
+// makes the next sample of a channel, from -1 to 1
+func (chip *opll) channelOutput(channel *opllChannel) float64 {
+	patch := chip.patch(channel)
+	modulator := &channel.operators[0]
+
+	//the modulator's own output is fed back into its phase, more feedback makes it closer to a sawtooth
+	feedback := 0.0
+	if fb := patch[3] & 0x07; fb != 0 {
+		feedback = (modulator.outputs[0] + modulator.outputs[1]) / 2 * math.Pi / 16 * float64(uint(1)<<(fb-1))
+	}
+	modulation := chip.operatorOutput(channel, patch, 0, feedback)
+	modulator.outputs[1] = modulator.outputs[0]
+	modulator.outputs[0] = modulation
+
+	//the modulator at full volume moves the carrier's phase by up to 4 pi either way
+	return chip.operatorOutput(channel, patch, 1, modulation*4*math.Pi)
+}
+
+// advances one operator and gets its output, op is 0 for the modulator and 1 for the carrier
+// the phase offset in radians is the modulation or feedback
+func (chip *opll) operatorOutput(channel *opllChannel, patch [8]uint8, op int, phaseOffset float64) float64 {
+	operator := &channel.operators[op]
+	flags := patch[op]
+
+	//the phase moves by the F-number scaled by the block and the multiplier
+	frequency := float64(channel.fnum) * float64(uint(1)<<channel.block) / (1 << 19) * opllSampleRate
+	frequency *= opllMultipliers[flags&0x0f]
+	if flags&0x40 == 0x40 {
+		frequency *= 1 + 0.0045*math.Sin(2*math.Pi*chip.vibrato)
+	}
+	operator.phase = math.Mod(operator.phase+frequency/opllSampleRate, 1)
+
+	chip.clockEnvelope(channel, patch, op)
+	if operator.state == opllOff {
+		return 0
+	}
+
+	//the total attenuation is the envelope, the operator's level, the key scaling and the tremolo
+	attenuation := operator.envelope
+	if op == 0 {
+		attenuation += float64(patch[2]&0x3f) * 0.75
+	} else {
+		attenuation += float64(channel.volume) * 3
+	}
+	if ksl := patch[2+op] >> 6; ksl != 0 {
+		level := opllKeyScaleLevels[channel.fnum>>5] - 6*float64(7-channel.block)
+		if level > 0 {
+			attenuation += level * [4]float64{0, 0.25, 0.5, 1}[ksl]
+		}
+	}
+	if flags&0x80 == 0x80 {
+		//a triangle wave from 0 to 4.8 dB
+		attenuation += 4.8 * (1 - math.Abs(2*chip.tremolo-1))
+	}
+
+	wave := math.Sin(2*math.Pi*operator.phase + phaseOffset)
+	//the rectify bits cut off the bottom half of the sine wave, 0x08 for the modulator and 0x10 for the carrier
+	if patch[3]&(0x08<<op) != 0 && wave < 0 {
+		wave = 0
+	}
+
+	return wave * math.Pow(10, -attenuation/20)
+}
+
+// moves an operator's envelope through attack, decay, sustain and release
+func (chip *opll) clockEnvelope(channel *opllChannel, patch [8]uint8, op int) {
+	operator := &channel.operators[op]
+	flags := patch[op]
+
+	//the key scale rate makes higher notes faster, by a lot more when the KSR bit is set
+	keyScale := uint8(channel.block<<1 | uint8(channel.fnum>>8))
+	if flags&0x10 == 0 {
+		keyScale >>= 2
+	}
+
+	attackRate := patch[4+op] >> 4
+	decayRate := patch[4+op] & 0x0f
+	sustainLevel := float64(patch[6+op]>>4) * 3
+	releaseRate := patch[6+op] & 0x0f
+	sustained := flags&0x20 == 0x20
+
+	switch operator.state {
+	case opllAttack:
+		rate := opllEffectiveRate(attackRate, keyScale)
+		if rate >= 60 {
+			operator.envelope = 0
+		} else if rate > 0 {
+			//the attack is exponential, fast at first then slowing as it reaches full volume
+			seconds := 2.826 / math.Pow(2, float64(rate-4)/4)
+			operator.envelope -= (operator.envelope + 3) * math.Log(17) / (seconds * opllSampleRate)
+		}
+		if operator.envelope <= 0 {
+			operator.envelope = 0
+			operator.state = opllDecay
+		}
+	case opllDecay:
+		operator.envelope += opllDecayStep(decayRate, keyScale)
+		if operator.envelope >= sustainLevel {
+			operator.envelope = sustainLevel
+			operator.state = opllSustain
+		}
+	case opllSustain:
+		//sustained instruments hold the note while the key is down, percussive ones keep fading at the release rate
+		if !sustained {
+			operator.envelope += opllDecayStep(releaseRate, keyScale)
+		}
+	case opllRelease:
+		//the sustain bit makes notes fade out slowly after the key is released
+		if channel.sustain {
+			releaseRate = 5
+		} else if !sustained {
+			releaseRate = 7
+		}
+		operator.envelope += opllDecayStep(releaseRate, keyScale)
+	}
+
+	if operator.envelope >= opllMaxAttenuation {
+		operator.envelope = opllMaxAttenuation
+		if operator.state != opllAttack {
+			operator.state = opllOff
+		}
+	}
+}
+
+// the rate used by the envelope, the 4 bit rate from the instrument plus the key scaling, 0 is stopped
+func opllEffectiveRate(rate uint8, keyScale uint8) uint8 {
+	if rate == 0 {
+		return 0
+	}
+	effective := rate*4 + keyScale
+	if effective > 63 {
+		effective = 63
+	}
+	return effective
+}
+
+// how many decibels a decay or release at a rate goes down each sample
+// the slowest decay takes about 20 seconds to go through the whole 48 dB, each step of the rate is twice as fast
+func opllDecayStep(rate uint8, keyScale uint8) float64 {
+	effective := opllEffectiveRate(rate, keyScale)
+	if effective == 0 {
+		return 0
+	}
+	if effective < 4 {
+		effective = 4
+	}
+
+	seconds := 19.64 / math.Pow(2, float64(effective-4)/4)
+	return opllMaxAttenuation / (seconds * opllSampleRate)
+}
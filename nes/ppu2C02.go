@@ -11,6 +11,7 @@ type PPU2C02 struct {
 	Cartridge *Cartridge
 
 	//name tables, lays out background tile data, NES has 2 physical name tables but 4 logical tables due to mirroring, 1 KB each
+	//four-screen cartridges and some mappers supply the memory for the other logical tables themselves
 	NameTable [2][1024]uint8
 	//pattern table, defines colors for sprites, 4 KB each
 	PatternTable [2][4096]uint8
@@ -200,6 +201,7 @@ func (ppu *PPU2C02) ConnectRenderer(ptr *sdl.Renderer) {
 // connects cartridge to PPU and graphics memory
 func (ppu *PPU2C02) ConnectCartridge(cart *Cartridge) {
 	ppu.Cartridge = cart
+	cart.ConnectNameTables(&ppu.NameTable)
 }
 
 // checks the PPUCTRL register to determine if the automatic increment after a PPU read/write should be 1 or 32, 1 is the next byte horizontally, but to read vertically you need to advance 32, because the memory is sequential, it's laid out in 32 byte "rows"
@@ -211,6 +213,15 @@ func (ppu *PPU2C02) incrementMode() uint16 {
 	}
 }
 
+// gets the address of the first row of a sprite tile, 8x8 sprites use the pattern table picked by PPUCTRL, 8x16 sprites use the low bit of the tile
+func (ppu *PPU2C02) spritePatternAddress(tile uint8) uint16 {
+	if ppu.PPUCTRL&0x20 == 0x20 {
+		return uint16(tile&1)<<12 | uint16(tile&0xfe)<<4
+	}
+
+	return uint16(ppu.PPUCTRL&0x08)<<9 | uint16(tile)<<4
+}
+
 func (ppu *PPU2C02) Reset() {
 	ppu.Cycle = 0
 	ppu.AddressByte = 0
@@ -340,29 +351,11 @@ func (ppu *PPU2C02) PPUWrite(addr uint16, data uint8) {
 		//usually rom but maybe could be ram
 		ppu.PatternTable[(addr&0x1000)>>12][addr&0x0fff] = data
 	} else if addr >= 0x2000 && addr <= 0x3eff { //name table memory
-		//checks what type of mirroring is used
+		//the cartridge decides which 1 KB of memory each of the 4 logical tables uses, with mirroring several logical tables share one physical table
 		//mirroring type is named after where you can find a duplicate of a physical nametable, ie horizontal means the nametable's duplicate is to it's left or right
 		//"mirroring" really means duplication, the memory values are not reflected to the other side, the are exact copies, and retain changes made to the counterpart
-		if ppu.Cartridge.Mirror == VERTICAL {
-			//the index into the table gets masked with 0x3ff because the table has a size of 0x400 so because its inexed at 0...
-			//table 0 and its mirror (top and bottom sides mirror eachother)
-			if (addr >= 2000 && addr <= 0x23bf) || (addr >= 2800 && addr <= 0x2bff) {
-				ppu.NameTable[0][addr&0x3ff] = data
-			}
-			//table 1 and its mirror
-			if (addr >= 2400 && addr <= 0x27ff) || (addr >= 0x2c00 && addr <= 0x2fff) {
-				ppu.NameTable[1][addr&0x3ff] = data
-			}
-		} else if ppu.Cartridge.Mirror == HORIZONTAL { //same thing except for a horizontal layout
-			//table 0 and its mirror (left and right sides mirror eachother)
-			if (addr >= 2000 && addr <= 0x23bf) || (addr >= 2400 && addr <= 0x27ff) {
-				ppu.NameTable[0][addr&0x3ff] = data
-			}
-			//table 1 and its mirror
-			if (addr >= 2800 && addr <= 0x2bff) || (addr >= 0x2c00 && addr <= 0x2fff) {
-				ppu.NameTable[1][addr&0x3ff] = data
-			}
-		}
+		//0x3000 - 0x3eff is a copy of 0x2000 - 0x2eff, so only the 2 bits above the table's 1 KB are used to pick the table
+		ppu.Cartridge.NameTablePages()[(addr>>10)&0x03][addr&0x3ff] = data
 	} else if addr >= 0x3f00 && addr <= 0x3fff { //palette memory
 		//mask the address for the palette index
 		addr &= 0x001f
@@ -380,7 +373,7 @@ func (ppu *PPU2C02) PPUWrite(addr uint16, data uint8) {
 	}
 }
 
-func (ppu PPU2C02) PPURead(addr uint16, readOnly bool) uint8 {
+func (ppu *PPU2C02) PPURead(addr uint16, readOnly bool) uint8 {
 	data, read := ppu.Cartridge.PPURead(addr, readOnly)
 
 	if read { //read into cartridge
@@ -389,29 +382,8 @@ func (ppu PPU2C02) PPURead(addr uint16, readOnly bool) uint8 {
 		//gets which pattern table by checking the highest 4 bits and uses the rest of the address as the index
 		data = ppu.PatternTable[(addr&0x1000)>>12][addr&0x0fff]
 	} else if addr >= 0x2000 && addr <= 0x3eff { //name table memory
-		//checks what type of mirroring is used
-		//mirroring type is named after where you can find a duplicate of a physical nametable, ie horizontal means the nametable's duplicate is to it's left or right
-		//"mirroring" really means duplication, the memory values are not reflected to the other side, the are exact copies, and retain changes made to the counterpart
-		if ppu.Cartridge.Mirror == VERTICAL {
-			//the index into the table gets masked with 0x3ff because the table has a size of 0x400 so because its inexed at 0...
-			//table 0 and its mirror (top and bottom sides mirror eachother)
-			if (addr >= 2000 && addr <= 0x23bf) || (addr >= 2800 && addr <= 0x2bff) {
-				data = ppu.NameTable[0][addr&0x3ff]
-			}
-			//table 1 and its mirror
-			if (addr >= 2400 && addr <= 0x27ff) || (addr >= 0x2c00 && addr <= 0x2fff) {
-				data = ppu.NameTable[1][addr&0x3ff]
-			}
-		} else if ppu.Cartridge.Mirror == HORIZONTAL { //same thing except for a horizontal layout
-			//table 0 and its mirror (left and right sides mirror eachother)
-			if (addr >= 2000 && addr <= 0x23bf) || (addr >= 2400 && addr <= 0x27ff) {
-				data = ppu.NameTable[0][addr&0x3ff]
-			}
-			//table 1 and its mirror
-			if (addr >= 2800 && addr <= 0x2bff) || (addr >= 0x2c00 && addr <= 0x2fff) {
-				data = ppu.NameTable[1][addr&0x3ff]
-			}
-		}
+		//the cartridge decides which 1 KB of memory each of the 4 logical tables uses, see PPUWrite
+		data = ppu.Cartridge.NameTablePages()[(addr>>10)&0x03][addr&0x3ff]
 	} else if addr >= 0x3f00 && addr <= 0x3fff { //palette memory
 		//mask the address for the palette index
 		addr &= 0x001f
@@ -472,7 +444,7 @@ func (ppu *PPU2C02) Clock() {
 				//reset the fineY back to 0
 				ppu.loopyVRAM &= 0x0fff
 				//put in the fineY
-				ppu.loopyVRAM |= fineY
+				ppu.loopyVRAM |= fineY << 12
 			} else {
 				//get courseY in a number
 				coarseY := (ppu.loopyVRAM & 0x03e0) >> 5
@@ -499,7 +471,8 @@ func (ppu *PPU2C02) Clock() {
 					//reset coarseY to 0
 					ppu.loopyVRAM &= 0x7c1f
 				} else {
-					//no wrapping, so just increment
+					//no wrapping, so just increment, clearing the old coarseY first
+					ppu.loopyVRAM &= 0x7c1f
 					ppu.loopyVRAM |= ((coarseY + 1) << 5)
 				}
 			}
@@ -584,6 +557,15 @@ func (ppu *PPU2C02) Clock() {
 			ppu.PPUSTATUS &= 0x7f
 		}
 
+		//let the mapper know a scanline started, mappers like the MMC5 count them
+		if ppu.Cycle == 1 {
+			ppu.Cartridge.PPUScanline(ppu.Scanline, ppu.PPUMASK&0x18 != 0)
+		}
+		//the background tiles for the next line start being fetched after the sprites
+		if ppu.Cycle == 321 && ppu.PPUMASK&0x18 != 0 {
+			ppu.Cartridge.PPUFetchingSprites(false)
+		}
+
 		if (ppu.Cycle >= 2 && ppu.Cycle < 258) || (ppu.Cycle >= 321 && ppu.Cycle < 338) {
 			updateShifters()
 
@@ -593,8 +575,10 @@ func (ppu *PPU2C02) Clock() {
 				loadBackgroundShifters()
 				ppu.bgNextId = ppu.PPURead(0x2000|(ppu.loopyVRAM&0x0fff), false)
 			case 2:
-				ppu.bgNextAttrib = ppu.PPURead(0x23c0|((ppu.loopyVRAM&2)<<11)|((ppu.loopyVRAM&1)<<10)|(((ppu.loopyVRAM&0b1111100000)>>2)<<3)|((ppu.loopyVRAM&0b11111)>>2), false)
-				if (ppu.loopyVRAM&0b1111100000)&0x0002 == 0x0002 {
+				//the attribute table is at the end of the nametable, each byte covers 4x4 tiles, so it uses the nametable bits and the top 3 bits of coarseY and coarseX
+				ppu.bgNextAttrib = ppu.PPURead(0x23c0|(ppu.loopyVRAM&0x0c00)|(((ppu.loopyVRAM&0b1111100000)>>7)<<3)|((ppu.loopyVRAM&0b11111)>>2), false)
+				//each 2 bits of the byte are a 2x2 tile quadrant, picked with bit 1 of coarseY and coarseX
+				if ((ppu.loopyVRAM&0b1111100000)>>5)&0x0002 == 0x0002 {
 					ppu.bgNextAttrib >>= 4
 				}
 				if (ppu.loopyVRAM&0b11111)&0x0002 == 0x0002 {
@@ -602,14 +586,29 @@ func (ppu *PPU2C02) Clock() {
 				}
 				ppu.bgNextAttrib &= 0x03
 			case 4:
-				ppu.bgNextLSB = ppu.PPURead((uint16(ppu.PPUCTRL&0b10000)<<12)+(uint16(ppu.bgNextId)<<4)+(ppu.loopyVRAM&0x7000), false)
+				//the background pattern table select bit moves up to A12, and fine Y picks the row of the tile
+				ppu.bgNextLSB = ppu.PPURead((uint16(ppu.PPUCTRL&0b10000)<<8)+(uint16(ppu.bgNextId)<<4)+((ppu.loopyVRAM&0x7000)>>12), false)
 			case 6:
-				ppu.bgNextMSB = ppu.PPURead((uint16(ppu.PPUCTRL&0b10000)<<12)+(uint16(ppu.bgNextId)<<4)+((ppu.loopyVRAM&0x7000)+8), false)
+				ppu.bgNextMSB = ppu.PPURead((uint16(ppu.PPUCTRL&0b10000)<<8)+(uint16(ppu.bgNextId)<<4)+((ppu.loopyVRAM&0x7000)>>12)+8, false)
 			case 7:
 				//done with 8 pixels, go to next 8
 				scrollXIncrement()
 			}
 		}
+		//sprite pattern fetches, sprites aren't drawn yet but the fetches still happen on the PPU bus, which mappers like the MMC3 watch to count scanlines
+		//with no sprites found for the next line the PPU fetches tile 0xff for each of the 8 sprite slots
+		if ppu.Cycle == 257 && ppu.PPUMASK&0x18 != 0 {
+			ppu.Cartridge.PPUFetchingSprites(true)
+		}
+		if ppu.Cycle >= 257 && ppu.Cycle <= 320 && ppu.PPUMASK&0x18 != 0 {
+			switch (ppu.Cycle - 257) % 8 {
+			case 4:
+				ppu.PPURead(ppu.spritePatternAddress(0xff), false)
+			case 6:
+				ppu.PPURead(ppu.spritePatternAddress(0xff)+8, false)
+			}
+		}
+
 		//done with a row, go to next Y
 		if ppu.Cycle == 256 {
 			scrollYIncrement()
@@ -627,7 +626,10 @@ func (ppu *PPU2C02) Clock() {
 	}
 
 	if ppu.Scanline == 240 {
-		//here to signify that scanline 240 takes no actions
+		//scanline 240 takes no actions, other than letting the mapper know rendering is done for the frame
+		if ppu.Cycle == 1 {
+			ppu.Cartridge.PPUScanline(ppu.Scanline, ppu.PPUMASK&0x18 != 0)
+		}
 	}
 
 	//this is when Vblank starts
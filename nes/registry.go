@@ -0,0 +1,187 @@
+package nes
+
+import (
+	"slices"
+	"sync"
+)
+
+// makes the mapper for a cartridge, from its mapper number, submapper, banks and mirroring
+// the cartridge's PRG and CHR memory are already loaded when it's called, and the error is returned when loading the cartridge
+type MapperConstructor func(cart *Cartridge) (Mapper, error)
+
+// registering a mapper with this submapper uses it for every submapper that isn't registered on its own
+const AnySubmapper uint8 = 0xff
+
+type mapperKey struct {
+	mapperID    uint16
+	submapperID uint8
+}
+
+// every mapper that can be used, the built in ones are registered in init
+var (
+	mapperRegistry = map[mapperKey]MapperConstructor{}
+	registryLock   sync.RWMutex
+)
+
+// adds a mapper that cartridges can use, replacing any mapper already registered with the same numbers, including the built in ones
+// the submapper can be AnySubmapper, a mapper registered for the exact submapper is used first
+func RegisterMapper(mapperID uint16, submapperID uint8, constructor MapperConstructor) {
+	registryLock.Lock()
+	defer registryLock.Unlock()
+
+	mapperRegistry[mapperKey{mapperID, submapperID}] = constructor
+}
+
+// finds the constructor for a mapper, falling back to the one for any submapper
+func lookupMapper(mapperID uint16, submapperID uint8) (MapperConstructor, bool) {
+	registryLock.RLock()
+	defer registryLock.RUnlock()
+
+	if constructor, ok := mapperRegistry[mapperKey{mapperID, submapperID}]; ok {
+		return constructor, true
+	}
+	constructor, ok := mapperRegistry[mapperKey{mapperID, AnySubmapper}]
+	return constructor, ok
+}
+
+// gets the numbers of every registered mapper in order, each listed once however many submappers it has
+func SupportedMappers() []uint16 {
+	registryLock.RLock()
+	defer registryLock.RUnlock()
+
+	var ids []uint16
+	for key := range mapperRegistry {
+		ids = append(ids, key.mapperID)
+	}
+	slices.Sort(ids)
+
+	return slices.Compact(ids)
+}
+
+// if a cartridge with the mapper and submapper can be loaded
+func MapperSupported(mapperID uint16, submapperID uint8) bool {
+	_, ok := lookupMapper(mapperID, submapperID)
+	return ok
+}
+
+// the built in mappers never fail, so they don't return an error
+func builtinMapper(create func(cart *Cartridge) Mapper) MapperConstructor {
+	return func(cart *Cartridge) (Mapper, error) {
+		return create(cart), nil
+	}
+}
+
+func init() {
+	builtins := map[uint16]func(cart *Cartridge) Mapper{
+		0: func(cart *Cartridge) Mapper {
+			return Mapper000{
+				PRGBanks: cart.PRGBanks,
+				CHRBanks: cart.CHRBanks,
+				CHRRAM:   cart.CHRRAM,
+			}
+		},
+		1: func(cart *Cartridge) Mapper {
+			return CreateMapper001(cart.PRGBanks, cart.CHRMemoryBanks(), cart.CHRRAM)
+		},
+		2: func(cart *Cartridge) Mapper {
+			return &Mapper002{
+				PRGBanks:     cart.PRGBanks,
+				CHRBanks:     cart.CHRBanks,
+				CHRRAM:       cart.CHRRAM,
+				busConflicts: cart.busConflicts(true),
+			}
+		},
+		3: func(cart *Cartridge) Mapper {
+			return &Mapper003{
+				PRGBanks:     cart.PRGBanks,
+				CHRBanks:     cart.CHRMemoryBanks(),
+				CHRRAM:       cart.CHRRAM,
+				busConflicts: cart.busConflicts(true),
+			}
+		},
+		4: func(cart *Cartridge) Mapper {
+			return CreateMapper004(cart.PRGBanks, cart.CHRMemoryBanks(), cart.CHRRAM, cart.Mirror)
+		},
+		5: func(cart *Cartridge) Mapper {
+			return CreateMapper005(cart.PRGBanks, cart.CHRMemoryBanks(), cart.CHRRAM)
+		},
+		7: func(cart *Cartridge) Mapper {
+			return &Mapper007{
+				PRGBanks: cart.PRGBanks,
+				CHRBanks: cart.CHRBanks,
+				CHRRAM:   cart.CHRRAM,
+				//ANROM has a chip that stops the conflicts, AOROM and AMROM don't, but most games for them avoid conflicts anyway, and submapper 2 turns them on
+				busConflicts: cart.busConflicts(false),
+			}
+		},
+		9: func(cart *Cartridge) Mapper {
+			return CreateMapper009(cart.PRGBanks, cart.CHRMemoryBanks(), cart.Mirror)
+		},
+		10: func(cart *Cartridge) Mapper {
+			return CreateMapper010(cart.PRGBanks, cart.CHRMemoryBanks(), cart.Mirror)
+		},
+		11: func(cart *Cartridge) Mapper {
+			return &Mapper011{
+				PRGBanks:     cart.PRGBanks,
+				CHRBanks:     cart.CHRMemoryBanks(),
+				CHRRAM:       cart.CHRRAM,
+				busConflicts: cart.busConflicts(true),
+			}
+		},
+		19: func(cart *Cartridge) Mapper {
+			return CreateMapper019(cart.PRGBanks, cart.CHRMemoryBanks(), cart.CHRRAM, cart.CHRMemory)
+		},
+		24: func(cart *Cartridge) Mapper {
+			return CreateMapper024(cart.MapperID, cart.PRGBanks, cart.CHRMemoryBanks(), cart.CHRRAM)
+		},
+		34: func(cart *Cartridge) Mapper {
+			return CreateMapper034(cart.SubmapperID, cart.PRGBanks, cart.CHRMemoryBanks(), cart.CHRRAM)
+		},
+		66: func(cart *Cartridge) Mapper {
+			return &Mapper066{
+				PRGBanks:     cart.PRGBanks,
+				CHRBanks:     cart.CHRMemoryBanks(),
+				CHRRAM:       cart.CHRRAM,
+				busConflicts: cart.busConflicts(true),
+			}
+		},
+		69: func(cart *Cartridge) Mapper {
+			return &Mapper069{
+				PRGBanks: cart.PRGBanks,
+				CHRBanks: cart.CHRMemoryBanks(),
+				CHRRAM:   cart.CHRRAM,
+				mirror:   cart.Mirror,
+			}
+		},
+		71: func(cart *Cartridge) Mapper {
+			return CreateMapper071(cart.SubmapperID, cart.PRGBanks, cart.CHRBanks, cart.CHRRAM, cart.Mirror)
+		},
+		79: func(cart *Cartridge) Mapper {
+			return &Mapper079{
+				PRGBanks: cart.PRGBanks,
+				CHRBanks: cart.CHRMemoryBanks(),
+				CHRRAM:   cart.CHRRAM,
+			}
+		},
+		85: func(cart *Cartridge) Mapper {
+			return CreateMapper085(cart.SubmapperID, cart.PRGBanks, cart.CHRMemoryBanks(), cart.CHRRAM)
+		},
+		206: func(cart *Cartridge) Mapper {
+			return &Mapper206{
+				PRGBanks: cart.PRGBanks,
+				CHRBanks: cart.CHRMemoryBanks(),
+				CHRRAM:   cart.CHRRAM,
+			}
+		},
+	}
+	//the VRC2 and VRC4 boards and the VRC6 boards only differ in their wiring, which the constructors pick from the mapper number
+	vrc4 := func(cart *Cartridge) Mapper {
+		return CreateMapper021(cart.MapperID, cart.SubmapperID, cart.PRGBanks, cart.CHRMemoryBanks(), cart.CHRRAM, cart.Mirror)
+	}
+	builtins[21], builtins[22], builtins[23], builtins[25] = vrc4, vrc4, vrc4, vrc4
+	builtins[26] = builtins[24]
+
+	for id, create := range builtins {
+		RegisterMapper(id, AnySubmapper, builtinMapper(create))
+	}
+}
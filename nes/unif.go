@@ -0,0 +1,220 @@
+package nes
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// returned when a UNIF chunk is cut off before the length it gives
+var ErrTruncatedChunk = errors.New("UNIF chunk is shorter than its length")
+
+// returned when the MIRR chunk has a value that isn't one of the UNIF mirroring types
+var ErrBadUNIFMirroring = errors.New("unknown UNIF mirroring type")
+
+// error returned when a UNIF file uses a board that isn't known
+type UnsupportedBoardError struct {
+	Board string
+}
+
+func (err *UnsupportedBoardError) Error() string {
+	return fmt.Sprintf("unsupported UNIF board %q", err.Board)
+}
+
+// UNIF names boards instead of numbering mappers, so this gives the mapper that implements each board
+// names are stored without the "NES-", "HVC-" or "UNL-" prefixes, which don't change the board
+var unifBoards = map[string]uint16{
+	"NROM": 0, "NROM-128": 0, "NROM-256": 0, "RROM": 0, "RROM-128": 0,
+
+	"SAROM": 1, "SBROM": 1, "SCROM": 1, "SC1ROM": 1, "SEROM": 1, "SFROM": 1, "SGROM": 1, "SHROM": 1, "SH1ROM": 1,
+	"SJROM": 1, "SKROM": 1, "SLROM": 1, "SL1ROM": 1, "SL2ROM": 1, "SL3ROM": 1, "SLRROM": 1, "SNROM": 1,
+	"SOROM": 1, "SUROM": 1, "SXROM": 1,
+
+	"UNROM": 2, "UOROM": 2,
+
+	"CNROM": 3,
+
+	"TBROM": 4, "TEROM": 4, "TFROM": 4, "TGROM": 4, "TKROM": 4, "TLROM": 4, "TL1ROM": 4, "TNROM": 4,
+	"TR1ROM": 4, "TSROM": 4, "TVROM": 4, "HKROM": 4,
+
+	"EKROM": 5, "ELROM": 5, "ETROM": 5, "EWROM": 5,
+
+	"AMROM": 7, "ANROM": 7, "AN1ROM": 7, "AOROM": 7,
+
+	"PNROM": 9, "PEEOROM": 9,
+
+	"FJROM": 10, "FKROM": 10,
+
+	"BNROM": 34, "AVE-NINA-01": 34, "AVE-NINA-02": 34,
+
+	"GNROM": 66, "MHROM": 66,
+
+	"BTR": 69, "JLROM": 69, "JSROM": 69,
+
+	"AVE-NINA-03": 79, "AVE-NINA-06": 79,
+
+	"DEROM": 206, "DE1ROM": 206, "DRROM": 206,
+}
+
+// values of the MIRR chunk
+const (
+	unifMirrorHorizontal = iota
+	unifMirrorVertical
+	unifMirrorScreenA
+	unifMirrorScreenB
+	unifMirrorFourScreen
+	unifMirrorMapper
+)
+
+// loads a cartridge from a UNIF file
+// UNIF has a 32 byte header, "UNIF", the revision and padding, followed by chunks that each have a 4 letter ID, a 4 byte little endian length and then the data
+// the game database, if one is given, can correct the board's mapper and also identifies boards that aren't known by name
+func loadUNIF(rom io.Reader, db *GameDB) (*Cartridge, error) {
+	header := make([]byte, 32)
+	if err := readSection(rom, header, ErrTruncatedHeader); err != nil {
+		return nil, err
+	}
+
+	cart := Cartridge{}
+	board := ""
+	//the ROM comes in up to 16 pieces each, PRG0 - PRGF and CHR0 - CHRF, that are put together in order
+	prg := [16][]byte{}
+	chr := [16][]byte{}
+
+	for {
+		chunkHeader := make([]byte, 8)
+		n, err := io.ReadFull(rom, chunkHeader)
+		if n == 0 && err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: chunk header", ErrTruncatedChunk)
+		}
+
+		//the length isn't trusted for allocating, the data is read up to it so a bad length can only use as much memory as the file has
+		id := string(chunkHeader[0:4])
+		length := binary.LittleEndian.Uint32(chunkHeader[4:8])
+		data, err := io.ReadAll(io.LimitReader(rom, int64(length)))
+		if err != nil {
+			return nil, err
+		}
+		if len(data) < int(length) {
+			return nil, fmt.Errorf("%s: %w: expected %d bytes, got %d", id, ErrTruncatedChunk, length, len(data))
+		}
+
+		switch {
+		case id == "MAPR":
+			board = cString(data)
+		case id == "NAME":
+			cart.Title = cString(data)
+		case id == "MIRR" && len(data) > 0:
+			switch data[0] {
+			case unifMirrorHorizontal:
+				cart.Mirror = HORIZONTAL
+			case unifMirrorVertical:
+				cart.Mirror = VERTICAL
+			case unifMirrorScreenA:
+				cart.Mirror = ONESCREEN_LO
+			case unifMirrorScreenB:
+				cart.Mirror = ONESCREEN_HI
+			case unifMirrorFourScreen:
+				cart.Mirror = FOURSCREEN
+			case unifMirrorMapper:
+				//the mapper switches the mirroring itself, so it starts out with the default until the game sets it
+				cart.Mirror = HORIZONTAL
+			default:
+				return nil, fmt.Errorf("%w: %d", ErrBadUNIFMirroring, data[0])
+			}
+		case id == "BATR":
+			cart.Battery = true
+		case id == "TVCI" && len(data) > 0:
+			switch data[0] {
+			case 0:
+				cart.Timing = NTSC
+			case 1:
+				cart.Timing = PAL
+			default:
+				cart.Timing = MULTIREGION
+			}
+		case strings.HasPrefix(id, "PRG"):
+			if index, ok := chunkIndex(id); ok {
+				prg[index] = data
+			}
+		case strings.HasPrefix(id, "CHR"):
+			if index, ok := chunkIndex(id); ok {
+				chr[index] = data
+			}
+		}
+	}
+
+	for _, data := range prg {
+		cart.PRGMemory = append(cart.PRGMemory, data...)
+	}
+	for _, data := range chr {
+		cart.CHRMemory = append(cart.CHRMemory, data...)
+	}
+
+	cart.PRGROMSize = len(cart.PRGMemory)
+	cart.CHRROMSize = len(cart.CHRMemory)
+	cart.PRGBanks = uint16((cart.PRGROMSize + 16383) / 16384)
+	cart.CHRBanks = uint16((cart.CHRROMSize + 8191) / 8192)
+	//the chunks don't have to add up to whole banks, some NROM boards have a single 8 KB PRG chunk
+	cart.PRGMemory = padROM(cart.PRGMemory, int(cart.PRGBanks)*16384)
+	cart.CHRMemory = padROM(cart.CHRMemory, int(cart.CHRBanks)*8192)
+	if cart.Battery {
+		cart.PRGNVRAMSize = 8192
+	}
+
+	mapper, known := unifBoards[unifBoardName(board)]
+	cart.MapperID = mapper
+
+	cart.allocateRAM()
+
+	found := db != nil && cart.correctFromGameDB(db)
+	if !known && !found {
+		return nil, &UnsupportedBoardError{Board: board}
+	}
+
+	if err := cart.checkROMSizes(); err != nil {
+		return nil, err
+	}
+	if err := cart.createMapper(); err != nil {
+		return nil, err
+	}
+
+	return &cart, nil
+}
+
+// takes the prefixes off a board name that don't change what the board is
+func unifBoardName(board string) string {
+	board = strings.ToUpper(board)
+	for _, prefix := range []string{"NES-", "HVC-", "UNL-"} {
+		board = strings.TrimPrefix(board, prefix)
+	}
+
+	return board
+}
+
+// gets which piece of PRG or CHR ROM a chunk is from the hex digit at the end of the ID
+func chunkIndex(id string) (int, bool) {
+	digit := id[3]
+	switch {
+	case digit >= '0' && digit <= '9':
+		return int(digit - '0'), true
+	case digit >= 'A' && digit <= 'F':
+		return int(digit-'A') + 10, true
+	}
+
+	return 0, false
+}
+
+// turns null terminated string data into a string
+func cString(data []byte) string {
+	if end := strings.IndexByte(string(data), 0); end >= 0 {
+		data = data[:end]
+	}
+
+	return strings.TrimSpace(string(data))
+}
@@ -0,0 +1,104 @@
+package nes
+
+import (
+	"encoding/binary"
+	"errors"
+	"testing"
+)
+
+// a UNIF chunk, the 4 letter ID, the little endian length and the data
+func unifChunk(id string, data []byte) []byte {
+	chunk := binary.LittleEndian.AppendUint32([]byte(id), uint32(len(data)))
+	return append(chunk, data...)
+}
+
+// a UNIF file with the 32 byte header and the given chunks
+func unifFile(chunks ...[]byte) []byte {
+	file := append([]byte("UNIF"), make([]byte, 28)...)
+	for _, chunk := range chunks {
+		file = append(file, chunk...)
+	}
+	return file
+}
+
+// PRG data with each byte set to the low byte of its offset, so reads show where they came from
+func unifPRG(size int) []byte {
+	prg := make([]byte, size)
+	for i := range prg {
+		prg[i] = uint8(i)
+	}
+	return prg
+}
+
+func TestUNIFSmallPRG(t *testing.T) {
+	//an 8 KB NROM board sees the same 8 KB in all 4 8 KB pieces of the ROM range
+	cart, err := LoadCartridgeBytes(unifFile(
+		unifChunk("MAPR", []byte("NES-NROM-128\x00")),
+		unifChunk("PRG0", unifPRG(0x2000)),
+		unifChunk("CHR0", make([]byte, 0x2000)),
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, addr := range []uint16{0x8005, 0xa005, 0xbfff, 0xfffc} {
+		if data, ok := cart.CPURead(addr, false); !ok || data != uint8(addr) {
+			t.Errorf("0x%04x read 0x%02x (%v), want 0x%02x", addr, data, ok, uint8(addr))
+		}
+	}
+}
+
+func TestUNIFMissingPRG(t *testing.T) {
+	for _, board := range []string{"NES-NROM-256", "NES-UNROM"} {
+		_, err := LoadCartridgeBytes(unifFile(
+			unifChunk("MAPR", []byte(board+"\x00")),
+			unifChunk("CHR0", make([]byte, 0x2000)),
+		))
+
+		var sizeErr *InvalidROMSizeError
+		if !errors.As(err, &sizeErr) || sizeErr.Area != "PRG" {
+			t.Errorf("%s: got error %v, want an invalid PRG size", board, err)
+		}
+	}
+}
+
+func TestUNIFMirroring(t *testing.T) {
+	tests := []struct {
+		value uint8
+		want  Mirror
+		err   error
+	}{
+		{unifMirrorHorizontal, HORIZONTAL, nil},
+		{unifMirrorVertical, VERTICAL, nil},
+		{unifMirrorScreenA, ONESCREEN_LO, nil},
+		{unifMirrorScreenB, ONESCREEN_HI, nil},
+		{unifMirrorFourScreen, FOURSCREEN, nil},
+		{unifMirrorMapper, HORIZONTAL, nil},
+		{6, 0, ErrBadUNIFMirroring},
+	}
+
+	for _, test := range tests {
+		cart, err := LoadCartridgeBytes(unifFile(
+			unifChunk("MAPR", []byte("NES-NROM-256\x00")),
+			unifChunk("PRG0", make([]byte, 0x8000)),
+			unifChunk("MIRR", []byte{test.value}),
+		))
+		if !errors.Is(err, test.err) {
+			t.Errorf("MIRR %d: got error %v, want %v", test.value, err, test.err)
+			continue
+		}
+		if test.err == nil && cart.Mirror != test.want {
+			t.Errorf("MIRR %d: mirroring %v, want %v", test.value, cart.Mirror, test.want)
+		}
+	}
+}
+
+func TestUNIFTruncatedChunk(t *testing.T) {
+	//the length says 4 GB but only a few bytes follow
+	file := unifFile(unifChunk("MAPR", []byte("NES-NROM-256\x00")))
+	file = append(file, 'P', 'R', 'G', '0', 0xff, 0xff, 0xff, 0xff, 1, 2, 3)
+
+	if _, err := LoadCartridgeBytes(file); !errors.Is(err, ErrTruncatedChunk) {
+		t.Errorf("got error %v, want %v", err, ErrTruncatedChunk)
+	}
+}
@@ -0,0 +1,62 @@
+package nes
+
+// the IRQ counter used by Konami's VRC4, VRC6 and VRC7
+// it counts up to 0xff either every CPU cycle, or every scanline using a prescaler that approximates a scanline from CPU cycles
+
+type vrcIRQ struct {
+	latch   uint8
+	counter uint8
+	//---- -MEA, cycle mode, enable, enable after acknowledgement
+	control   uint8
+	prescaler int
+	active    bool
+}
+
+// the prescaler counts down 3 each CPU cycle, which is 1 each PPU cycle, so it runs out once every 341 PPU cycles, one scanline
+const vrcScanlineCycles = 341
+
+func (irq *vrcIRQ) writeControl(data uint8) {
+	irq.control = data & 0x07
+	if irq.control&0x02 == 0x02 {
+		irq.counter = irq.latch
+		irq.prescaler = vrcScanlineCycles
+	}
+	irq.active = false
+}
+
+// acknowledging the IRQ also puts the A bit into the E bit
+func (irq *vrcIRQ) acknowledge() {
+	irq.active = false
+	if irq.control&0x01 == 0x01 {
+		irq.control |= 0x02
+	} else {
+		irq.control &= 0x05
+	}
+}
+
+func (irq *vrcIRQ) clock() {
+	if irq.control&0x02 == 0 {
+		return
+	}
+
+	if irq.control&0x04 == 0x04 {
+		irq.clockCounter()
+		return
+	}
+
+	irq.prescaler -= 3
+	if irq.prescaler <= 0 {
+		irq.prescaler += vrcScanlineCycles
+		irq.clockCounter()
+	}
+}
+
+// the counter fires and reloads when it overflows
+func (irq *vrcIRQ) clockCounter() {
+	if irq.counter == 0xff {
+		irq.counter = irq.latch
+		irq.active = true
+	} else {
+		irq.counter++
+	}
+}
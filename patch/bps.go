@@ -0,0 +1,120 @@
+package patch
+
+import (
+	"encoding/binary"
+	"hash/crc32"
+)
+
+// commands that make up a BPS patch
+const (
+	bpsSourceRead = iota
+	bpsTargetRead
+	bpsSourceCopy
+	bpsTargetCopy
+)
+
+// applies a BPS patch
+// BPS stores the source size, target size and metadata, then builds the target out of commands that copy from the source, the patch, or the target itself
+// the last 12 bytes are the CRC32s of the source ROM, the target ROM and the patch itself, which are all checked
+func ApplyBPS(rom []byte, patch []byte) ([]byte, error) {
+	if len(patch) < 4+12 {
+		return nil, ErrCorruptPatch
+	}
+	if err := checkFooter(rom, patch); err != nil {
+		return nil, err
+	}
+
+	pos := 4
+	sourceSize, err := readNumber(patch, &pos)
+	if err != nil {
+		return nil, err
+	}
+	targetSize, err := readNumber(patch, &pos)
+	if err != nil {
+		return nil, err
+	}
+	metadataSize, err := readNumber(patch, &pos)
+	if err != nil {
+		return nil, err
+	}
+	if sourceSize != uint64(len(rom)) {
+		return nil, ErrSourceSize
+	}
+	if targetSize > 1<<31 || metadataSize > uint64(len(patch)) {
+		return nil, ErrCorruptPatch
+	}
+	//metadata is just text describing the patch, it isn't needed
+	pos += int(metadataSize)
+
+	out := make([]byte, targetSize)
+	end := len(patch) - 12
+	outPos := 0
+	//the copy commands move these around with relative offsets
+	sourceRel := 0
+	targetRel := 0
+
+	for pos < end {
+		data, err := readNumber(patch, &pos)
+		if err != nil {
+			return nil, err
+		}
+		command := data & 3
+		length := int(data>>2) + 1
+		if outPos+length > len(out) {
+			return nil, ErrCorruptPatch
+		}
+
+		switch command {
+		case bpsSourceRead:
+			//copy from the same spot in the source
+			if outPos+length > len(rom) {
+				return nil, ErrCorruptPatch
+			}
+			copy(out[outPos:], rom[outPos:outPos+length])
+		case bpsTargetRead:
+			//copy new data stored in the patch
+			if pos+length > end {
+				return nil, ErrCorruptPatch
+			}
+			copy(out[outPos:], patch[pos:pos+length])
+			pos += length
+		case bpsSourceCopy, bpsTargetCopy:
+			offset, err := readNumber(patch, &pos)
+			if err != nil {
+				return nil, err
+			}
+			//the lowest bit is the sign of the offset
+			move := int(offset >> 1)
+			if offset&1 == 1 {
+				move = -move
+			}
+
+			if command == bpsSourceCopy {
+				sourceRel += move
+				if sourceRel < 0 || sourceRel+length > len(rom) {
+					return nil, ErrCorruptPatch
+				}
+				copy(out[outPos:], rom[sourceRel:sourceRel+length])
+				sourceRel += length
+			} else {
+				targetRel += move
+				if targetRel < 0 || targetRel >= outPos {
+					return nil, ErrCorruptPatch
+				}
+				//copied one byte at a time since the copy can overlap what it's writing, which is used to repeat patterns
+				for i := 0; i < length; i++ {
+					out[outPos+i] = out[targetRel]
+					targetRel++
+				}
+			}
+		}
+
+		outPos += length
+	}
+
+	if crc32.ChecksumIEEE(out) != binary.LittleEndian.Uint32(patch[len(patch)-8:]) {
+		return nil, ErrTargetChecksum
+	}
+
+	return out, nil
+}
@@ -0,0 +1,90 @@
+package patch
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+// a BPS command, the length and type share one number
+func bpsCommand(command int, length int) []byte {
+	return encodeNumber(uint64((length-1)<<2 | command))
+}
+
+// a relative offset for the copy commands, the low bit is the sign
+func bpsOffset(move int) []byte {
+	if move < 0 {
+		return encodeNumber(uint64(-move)<<1 | 1)
+	}
+	return encodeNumber(uint64(move) << 1)
+}
+
+// the sizes and metadata at the start of a BPS patch from testSource to testTarget
+func bpsHeader() []byte {
+	header := []byte("BPS1")
+	header = append(header, encodeNumber(uint64(len(testSource)))...)
+	header = append(header, encodeNumber(uint64(len(testTarget)))...)
+	header = append(header, encodeNumber(4)...)
+	return append(header, "test"...)
+}
+
+// the body of a BPS patch from testSource to testTarget, without the footer, using every command
+func bpsBody() []byte {
+	body := bpsHeader()
+	//"hell" from the source
+	body = append(body, bpsCommand(bpsSourceRead, 4)...)
+	//"O" from the patch
+	body = append(body, bpsCommand(bpsTargetRead, 1)...)
+	body = append(body, 'O')
+	//" world, this is a rom" from source offset 5
+	body = append(body, bpsCommand(bpsSourceCopy, 21)...)
+	body = append(body, bpsOffset(5)...)
+	//"!" from the patch, then repeated by copying the target onto itself
+	body = append(body, bpsCommand(bpsTargetRead, 1)...)
+	body = append(body, '!')
+	body = append(body, bpsCommand(bpsTargetCopy, 3)...)
+	return append(body, bpsOffset(26)...)
+}
+
+func TestApplyBPS(t *testing.T) {
+	valid := appendFooter(bpsBody(), testSource, testTarget)
+	damaged := bytes.Clone(valid)
+	damaged[len(damaged)-13] ^= 0xff
+
+	//a source copy that moves back before the start of the ROM
+	badOffset := append(bpsHeader(), bpsCommand(bpsSourceCopy, 4)...)
+	badOffset = append(badOffset, bpsOffset(-1)...)
+	//a target copy from data that hasn't been written yet
+	badTarget := append(bpsHeader(), bpsCommand(bpsTargetCopy, 4)...)
+	badTarget = append(badTarget, bpsOffset(0)...)
+	cut := bpsBody()
+
+	tests := []struct {
+		name   string
+		source []byte
+		patch  []byte
+		err    error
+	}{
+		{"apply", testSource, valid, nil},
+		{"wrong rom", testTarget, valid, ErrSourceChecksum},
+		{"bad patch crc", testSource, damaged, ErrPatchChecksum},
+		{"bad target crc", testSource, appendFooter(bpsBody(), testSource, testSource), ErrTargetChecksum},
+		{"wrong rom size", testSource[:10], appendFooter(bpsBody(), testSource[:10], testTarget), ErrSourceSize},
+		{"source offset out of range", testSource, appendFooter(badOffset, testSource, testTarget), ErrCorruptPatch},
+		{"target offset out of range", testSource, appendFooter(badTarget, testSource, testTarget), ErrCorruptPatch},
+		{"truncated", testSource, valid[:len(valid)-4], ErrPatchChecksum},
+		{"truncated command", testSource, appendFooter(cut[:len(cut)-1], testSource, testTarget), ErrCorruptPatch},
+		{"too short", testSource, []byte("BPS1"), ErrCorruptPatch},
+	}
+
+	for _, test := range tests {
+		out, err := Apply(test.source, test.patch)
+		if !errors.Is(err, test.err) {
+			t.Errorf("%s: got error %v, want %v", test.name, err, test.err)
+			continue
+		}
+		if test.err == nil && !bytes.Equal(out, testTarget) {
+			t.Errorf("%s: got %q, want %q", test.name, out, testTarget)
+		}
+	}
+}
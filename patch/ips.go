@@ -0,0 +1,63 @@
+package patch
+
+// applies an IPS patch
+// IPS is a list of records, each a 3 byte big endian offset and a 2 byte size followed by that many bytes to write
+// a size of 0 is a run length encoded record, a 2 byte count and the 1 byte value to repeat
+// the list ends with "EOF", which can be followed by a 3 byte size to truncate the output to
+func ApplyIPS(rom []byte, patch []byte) ([]byte, error) {
+	out := append([]byte(nil), rom...)
+	pos := 5
+
+	for {
+		if pos+3 > len(patch) {
+			return nil, ErrCorruptPatch
+		}
+		if string(patch[pos:pos+3]) == "EOF" {
+			pos += 3
+			break
+		}
+
+		if pos+5 > len(patch) {
+			return nil, ErrCorruptPatch
+		}
+		offset := int(patch[pos])<<16 | int(patch[pos+1])<<8 | int(patch[pos+2])
+		size := int(patch[pos+3])<<8 | int(patch[pos+4])
+		pos += 5
+
+		var data []byte
+		if size == 0 {
+			//run length encoded record
+			if pos+3 > len(patch) {
+				return nil, ErrCorruptPatch
+			}
+			count := int(patch[pos])<<8 | int(patch[pos+1])
+			data = make([]byte, count)
+			for i := range data {
+				data[i] = patch[pos+2]
+			}
+			pos += 3
+		} else {
+			if pos+size > len(patch) {
+				return nil, ErrCorruptPatch
+			}
+			data = patch[pos : pos+size]
+			pos += size
+		}
+
+		//records can write past the end of the ROM, which makes it bigger
+		if offset+len(data) > len(out) {
+			out = append(out, make([]byte, offset+len(data)-len(out))...)
+		}
+		copy(out[offset:], data)
+	}
+
+	//optional truncation extension
+	if pos+3 <= len(patch) {
+		size := int(patch[pos])<<16 | int(patch[pos+1])<<8 | int(patch[pos+2])
+		if size < len(out) {
+			out = out[:size]
+		}
+	}
+
+	return out, nil
+}
@@ -0,0 +1,87 @@
+package patch
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+// an IPS record writing data at a 3 byte offset
+func ipsRecord(offset int, data []byte) []byte {
+	record := []byte{uint8(offset >> 16), uint8(offset >> 8), uint8(offset), uint8(len(data) >> 8), uint8(len(data))}
+	return append(record, data...)
+}
+
+// an IPS run length encoded record, count copies of value
+func ipsRunRecord(offset int, count int, value uint8) []byte {
+	return []byte{uint8(offset >> 16), uint8(offset >> 8), uint8(offset), 0, 0, uint8(count >> 8), uint8(count), value}
+}
+
+func ipsPatch(records ...[]byte) []byte {
+	patch := []byte("PATCH")
+	for _, record := range records {
+		patch = append(patch, record...)
+	}
+	return append(patch, "EOF"...)
+}
+
+func TestApplyIPS(t *testing.T) {
+	tests := []struct {
+		name  string
+		patch []byte
+		want  []byte
+		err   error
+	}{
+		{
+			name:  "records",
+			patch: ipsPatch(ipsRecord(4, []byte("O")), ipsRecord(26, []byte("!!!!"))),
+			want:  testTarget,
+		},
+		{
+			name:  "run length",
+			patch: ipsPatch(ipsRecord(4, []byte("O")), ipsRunRecord(26, 4, '!')),
+			want:  testTarget,
+		},
+		{
+			name:  "3 byte offset",
+			patch: ipsPatch(ipsRecord(0x010000, []byte{0xaa})),
+			want:  append(append(append([]byte{}, testSource...), make([]byte, 0x010000-len(testSource))...), 0xaa),
+		},
+		{
+			name:  "truncate extension",
+			patch: append(ipsPatch(ipsRecord(0, []byte("J"))), 0, 0, 5),
+			want:  []byte("Jello"),
+		},
+		{
+			name:  "no EOF",
+			patch: ipsPatch(ipsRecord(4, []byte("O")))[:11],
+			err:   ErrCorruptPatch,
+		},
+		{
+			name:  "record cut off",
+			patch: append([]byte("PATCH"), ipsRecord(4, []byte("OOOO"))[:7]...),
+			err:   ErrCorruptPatch,
+		},
+		{
+			name:  "run record cut off",
+			patch: append([]byte("PATCH"), ipsRunRecord(4, 4, '!')[:6]...),
+			err:   ErrCorruptPatch,
+		},
+	}
+
+	for _, test := range tests {
+		out, err := Apply(testSource, test.patch)
+		if !errors.Is(err, test.err) {
+			t.Errorf("%s: got error %v, want %v", test.name, err, test.err)
+			continue
+		}
+		if test.err == nil && !bytes.Equal(out, test.want) {
+			t.Errorf("%s: got %q, want %q", test.name, out, test.want)
+		}
+	}
+
+	//the ROM passed in is left alone
+	if !bytes.Equal(testSource, []byte("hello world, this is a rom")) {
+		t.Errorf("source changed to %q", testSource)
+	}
+}
@@ -0,0 +1,83 @@
+package patch
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// errors returned when a patch can't be applied, checked with errors.Is
+var (
+	ErrUnknownFormat  = errors.New("patch is not in IPS, UPS or BPS format")
+	ErrCorruptPatch   = errors.New("patch data is corrupt or truncated")
+	ErrSourceChecksum = errors.New("ROM checksum doesn't match the one the patch was made for")
+	ErrTargetChecksum = errors.New("patched ROM checksum doesn't match the one stored in the patch")
+	ErrPatchChecksum  = errors.New("patch checksum doesn't match, the patch file is damaged")
+	ErrSourceSize     = errors.New("ROM size doesn't match the one the patch was made for")
+)
+
+// patch file extensions searched for next to a ROM, in the order they are tried
+var patchExtensions = []string{".ips", ".ups", ".bps"}
+
+// applies a patch to a ROM image, figuring out the format from the start of the patch
+// the ROM passed in isn't changed, a new patched copy is returned
+func Apply(rom []byte, patch []byte) ([]byte, error) {
+	switch {
+	case bytes.HasPrefix(patch, []byte("PATCH")):
+		return ApplyIPS(rom, patch)
+	case bytes.HasPrefix(patch, []byte("UPS1")):
+		return ApplyUPS(rom, patch)
+	case bytes.HasPrefix(patch, []byte("BPS1")):
+		return ApplyBPS(rom, patch)
+	}
+
+	return nil, ErrUnknownFormat
+}
+
+// reads a patch file and applies it to a ROM image
+func ApplyFile(rom []byte, patchPath string) ([]byte, error) {
+	patch, err := os.ReadFile(patchPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return Apply(rom, patch)
+}
+
+// looks for a patch with the same name as the ROM but a patch extension, so game.nes is patched by game.ips, game.ups or game.bps
+// returns an empty string if there isn't one
+func FindPatch(romPath string) string {
+	base := strings.TrimSuffix(romPath, filepath.Ext(romPath))
+	for _, ext := range patchExtensions {
+		patchPath := base + ext
+		if info, err := os.Stat(patchPath); err == nil && !info.IsDir() {
+			return patchPath
+		}
+	}
+
+	return ""
+}
+
+// reads the variable length numbers used by UPS and BPS, 7 bits at a time with the high bit marking the last byte
+// each byte after the first has 1 added to it before shifting, so there is only one way to write each number
+func readNumber(patch []byte, pos *int) (uint64, error) {
+	value := uint64(0)
+	shift := uint64(1)
+
+	for {
+		if *pos >= len(patch) || shift > 1<<56 {
+			return 0, ErrCorruptPatch
+		}
+		data := patch[*pos]
+		*pos++
+
+		value += uint64(data&0x7f) * shift
+		if data&0x80 == 0x80 {
+			return value, nil
+		}
+		shift <<= 7
+		value += shift
+	}
+}
@@ -0,0 +1,77 @@
+package patch
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// the ROM the test patches are made for, and what they turn it into
+var (
+	testSource = []byte("hello world, this is a rom")
+	testTarget = []byte("hellO world, this is a rom!!!!")
+)
+
+// writes a number in the variable length format UPS and BPS use
+func encodeNumber(value uint64) []byte {
+	var out []byte
+	for {
+		data := uint8(value & 0x7f)
+		value >>= 7
+		if value == 0 {
+			return append(out, 0x80|data)
+		}
+		out = append(out, data)
+		value--
+	}
+}
+
+// adds the source, target and patch CRC32s that end UPS and BPS patches
+func appendFooter(patch []byte, source []byte, target []byte) []byte {
+	patch = binary.LittleEndian.AppendUint32(patch, crc32.ChecksumIEEE(source))
+	patch = binary.LittleEndian.AppendUint32(patch, crc32.ChecksumIEEE(target))
+	return binary.LittleEndian.AppendUint32(patch, crc32.ChecksumIEEE(patch))
+}
+
+func TestReadNumber(t *testing.T) {
+	for _, value := range []uint64{0, 1, 0x7f, 0x80, 0x4000, 0x123456, 1 << 40} {
+		pos := 0
+		got, err := readNumber(encodeNumber(value), &pos)
+		if err != nil || got != value {
+			t.Errorf("%d read back as %d (%v)", value, got, err)
+		}
+	}
+
+	//the last byte has the high bit set, without it the number runs off the end
+	pos := 0
+	if _, err := readNumber([]byte{0x01, 0x02}, &pos); !errors.Is(err, ErrCorruptPatch) {
+		t.Errorf("unterminated number gave %v", err)
+	}
+}
+
+func TestApplyUnknownFormat(t *testing.T) {
+	if _, err := Apply(testSource, []byte("NOTAPATCH")); !errors.Is(err, ErrUnknownFormat) {
+		t.Errorf("got %v, want %v", err, ErrUnknownFormat)
+	}
+}
+
+func TestFindPatch(t *testing.T) {
+	dir := t.TempDir()
+	romPath := filepath.Join(dir, "game.nes")
+	if got := FindPatch(romPath); got != "" {
+		t.Errorf("found %q with no patch", got)
+	}
+
+	//IPS is tried before BPS
+	for _, name := range []string{"game.bps", "game.ips"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got, want := FindPatch(romPath), filepath.Join(dir, "game.ips"); got != want {
+		t.Errorf("found %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,84 @@
+package patch
+
+import (
+	"encoding/binary"
+	"hash/crc32"
+)
+
+// applies a UPS patch
+// UPS stores the source and target sizes, then hunks of a relative offset followed by bytes to XOR into the ROM, ending with a 0
+// the last 12 bytes are the CRC32s of the source ROM, the target ROM and the patch itself, which are all checked
+func ApplyUPS(rom []byte, patch []byte) ([]byte, error) {
+	if len(patch) < 4+12 {
+		return nil, ErrCorruptPatch
+	}
+	if err := checkFooter(rom, patch); err != nil {
+		return nil, err
+	}
+
+	pos := 4
+	sourceSize, err := readNumber(patch, &pos)
+	if err != nil {
+		return nil, err
+	}
+	targetSize, err := readNumber(patch, &pos)
+	if err != nil {
+		return nil, err
+	}
+	if sourceSize != uint64(len(rom)) {
+		return nil, ErrSourceSize
+	}
+	if targetSize > 1<<31 {
+		return nil, ErrCorruptPatch
+	}
+
+	out := make([]byte, targetSize)
+	copy(out, rom)
+
+	end := len(patch) - 12
+	outPos := uint64(0)
+	for pos < end {
+		offset, err := readNumber(patch, &pos)
+		if err != nil {
+			return nil, err
+		}
+		outPos += offset
+
+		for {
+			if pos >= end {
+				return nil, ErrCorruptPatch
+			}
+			data := patch[pos]
+			pos++
+			if data == 0 {
+				break
+			}
+			if outPos < targetSize {
+				out[outPos] ^= data
+			}
+			outPos++
+		}
+		//the 0 ending the hunk also counts as a byte
+		outPos++
+	}
+
+	if crc32.ChecksumIEEE(out) != binary.LittleEndian.Uint32(patch[len(patch)-8:]) {
+		return nil, ErrTargetChecksum
+	}
+
+	return out, nil
+}
+
+// checks the source and patch CRC32s at the end of a UPS or BPS patch
+func checkFooter(rom []byte, patch []byte) error {
+	footer := patch[len(patch)-12:]
+
+	if crc32.ChecksumIEEE(patch[:len(patch)-4]) != binary.LittleEndian.Uint32(footer[8:]) {
+		return ErrPatchChecksum
+	}
+	if crc32.ChecksumIEEE(rom) != binary.LittleEndian.Uint32(footer[0:]) {
+		return ErrSourceChecksum
+	}
+
+	return nil
+}
@@ -0,0 +1,54 @@
+package patch
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+// the body of a UPS patch from testSource to testTarget, without the footer
+// hunks skip ahead by their offset, then XOR bytes in until a 0
+func upsBody() []byte {
+	body := []byte("UPS1")
+	body = append(body, encodeNumber(uint64(len(testSource)))...)
+	body = append(body, encodeNumber(uint64(len(testTarget)))...)
+	body = append(body, encodeNumber(4)...)
+	body = append(body, 'o'^'O', 0)
+	//the first hunk ended at 6, counting its 0
+	body = append(body, encodeNumber(26-6)...)
+	return append(body, '!', '!', '!', '!', 0)
+}
+
+func TestApplyUPS(t *testing.T) {
+	valid := appendFooter(upsBody(), testSource, testTarget)
+	damaged := bytes.Clone(valid)
+	damaged[6] ^= 0xff
+	cut := upsBody()
+
+	tests := []struct {
+		name   string
+		source []byte
+		patch  []byte
+		err    error
+	}{
+		{"apply", testSource, valid, nil},
+		{"wrong rom", testTarget, valid, ErrSourceChecksum},
+		{"bad patch crc", testSource, damaged, ErrPatchChecksum},
+		{"bad target crc", testSource, appendFooter(upsBody(), testSource, testSource), ErrTargetChecksum},
+		{"wrong rom size", testSource[:10], appendFooter(upsBody(), testSource[:10], testTarget), ErrSourceSize},
+		{"truncated", testSource, valid[:len(valid)-4], ErrPatchChecksum},
+		{"truncated hunk", testSource, appendFooter(cut[:len(cut)-2], testSource, testTarget), ErrCorruptPatch},
+		{"too short", testSource, []byte("UPS1"), ErrCorruptPatch},
+	}
+
+	for _, test := range tests {
+		out, err := Apply(test.source, test.patch)
+		if !errors.Is(err, test.err) {
+			t.Errorf("%s: got error %v, want %v", test.name, err, test.err)
+			continue
+		}
+		if test.err == nil && !bytes.Equal(out, testTarget) {
+			t.Errorf("%s: got %q, want %q", test.name, out, testTarget)
+		}
+	}
+}